@@ -0,0 +1,336 @@
+// Package advisor recommends how to co-schedule a mix of jobs based on the measured pairwise slowdowns
+package advisor
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/jbreitbart/coBench/stats"
+)
+
+// Objectives of the advisor
+const (
+	// maximize the system throughput (Σ 1/slowdown)
+	ThroughputObjective = "stp"
+	// minimize the worst slowdown of any job
+	WorstCaseObjective = "max"
+)
+
+// DefaultExactLimit is the maximum number of jobs for which the optimal schedule is computed
+const DefaultExactLimit = 12
+
+// OptionsT configures the advisor
+type OptionsT struct {
+	// jobs per node
+	Slots     int
+	Objective string
+	// choose the best CAT split per pair, only supported with 2 slots
+	CAT    bool
+	Metric string
+	// up to this number of jobs the schedule is computed exactly, above a heuristic is used
+	ExactLimit int
+}
+
+// GroupT is a set of jobs executed on the same node
+type GroupT struct {
+	Jobs []string
+	// CAT bits of every job, 0 means the cache is shared
+	CATBits []int
+	// predicted slowdown of every job
+	Slowdowns []float64
+}
+
+// ScheduleT is the recommended schedule and its predicted metrics
+type ScheduleT struct {
+	Groups      []GroupT
+	STP         float64
+	ANTT        float64
+	MaxSlowdown float64
+	Fairness    float64
+	// false if a heuristic was used
+	Exact bool
+}
+
+// interference of job i co-scheduled with job j
+type edgeT struct {
+	// slowdown of i and j
+	Slowdowns [2]float64
+	CATBits   [2]int
+	Valid     bool
+}
+
+type graphT struct {
+	data  *stats.StatsT
+	jobs  []string
+	edges [][]edgeT
+	opts  OptionsT
+}
+
+// Advise computes the schedule of jobs measured in data optimizing the objective. Jobs may contain the same application
+// multiple times.
+func Advise(data *stats.StatsT, jobs []string, opts OptionsT) (*ScheduleT, error) {
+	if opts.Slots < 2 {
+		return nil, fmt.Errorf("At least 2 slots per node are required")
+	}
+	if opts.CAT && opts.Slots != 2 {
+		return nil, fmt.Errorf("CAT splits are only supported with 2 slots per node")
+	}
+	if opts.Objective != ThroughputObjective && opts.Objective != WorstCaseObjective {
+		return nil, fmt.Errorf("Unknown objective %v", opts.Objective)
+	}
+	if opts.ExactLimit == 0 {
+		opts.ExactLimit = DefaultExactLimit
+	}
+	if len(jobs) == 0 {
+		return nil, fmt.Errorf("No jobs provided")
+	}
+
+	g := newGraph(data, jobs, opts)
+
+	var groups [][]int
+	exact := len(jobs) <= opts.ExactLimit
+	if exact {
+		groups = g.exact()
+	} else {
+		groups = g.heuristic()
+	}
+	if groups == nil {
+		return nil, fmt.Errorf("No schedule possible with the measured co-scheduling data")
+	}
+
+	return g.schedule(groups, exact), nil
+}
+
+func newGraph(data *stats.StatsT, jobs []string, opts OptionsT) *graphT {
+	g := graphT{data: data, jobs: jobs, opts: opts}
+
+	g.edges = make([][]edgeT, len(jobs))
+	for i := range jobs {
+		g.edges[i] = make([]edgeT, len(jobs))
+	}
+
+	for i := range jobs {
+		for j := i + 1; j < len(jobs); j++ {
+			e := g.measure(jobs[i], jobs[j])
+			g.edges[i][j] = e
+			g.edges[j][i] = edgeT{Slowdowns: [2]float64{e.Slowdowns[1], e.Slowdowns[0]}, CATBits: [2]int{e.CATBits[1], e.CATBits[0]}, Valid: e.Valid}
+		}
+	}
+
+	return &g
+}
+
+func (g *graphT) measure(app0 string, app1 string) edgeT {
+	if g.opts.CAT {
+		objective := stats.ObjectiveT{Kind: stats.SumSlowdownObjective}
+		if g.opts.Objective == WorstCaseObjective {
+			objective.Kind = stats.MaxSlowdownObjective
+		}
+		rec := g.data.FindBestPartition(app0, app1, objective, g.opts.Metric, stats.DefaultCILevel)
+		if rec == nil || rec.Best == nil {
+			return edgeT{}
+		}
+		return edgeT{Slowdowns: rec.Best.Slowdowns, CATBits: rec.Best.CATBits, Valid: true}
+	}
+
+	m := g.data.CoSchedPairMetrics(app0, app1, g.opts.Metric)
+	if m == nil {
+		return edgeT{}
+	}
+	return edgeT{Slowdowns: m.Slowdowns, Valid: true}
+}
+
+// predicted slowdowns of the jobs in group. With more than 2 jobs the interference of all pairs is added up.
+func (g *graphT) slowdowns(group []int) ([]float64, bool) {
+	ret := make([]float64, len(group))
+	for a, i := range group {
+		ret[a] = 1
+		for _, j := range group {
+			if i == j {
+				continue
+			}
+			if !g.edges[i][j].Valid {
+				return nil, false
+			}
+			ret[a] += g.edges[i][j].Slowdowns[0] - 1
+		}
+	}
+	return ret, true
+}
+
+// cost of a complete schedule, lower is better
+func (g *graphT) cost(groups [][]int) float64 {
+	stp := 0.0
+	worst := 0.0
+	for _, group := range groups {
+		s, ok := g.slowdowns(group)
+		if !ok {
+			return math.Inf(1)
+		}
+		for _, v := range s {
+			stp += 1 / v
+			worst = math.Max(worst, v)
+		}
+	}
+
+	if g.opts.Objective == WorstCaseObjective {
+		return worst
+	}
+	return -stp
+}
+
+// enumerates every partition of the jobs into groups of g.opts.Slots jobs
+func (g *graphT) exact() [][]int {
+	var best [][]int
+	bestCost := math.Inf(1)
+
+	used := make([]bool, len(g.jobs))
+	var current [][]int
+
+	var addGroup func(remaining int)
+	var fillGroup func(group []int, start int, size int, remaining int)
+
+	addGroup = func(remaining int) {
+		if remaining == 0 {
+			if c := g.cost(current); c < bestCost {
+				bestCost = c
+				best = copyGroups(current)
+			}
+			return
+		}
+		// the first unused job is always part of the next group, so every partition is created only once
+		for i := range used {
+			if !used[i] {
+				size := g.opts.Slots
+				if remaining < size {
+					size = remaining
+				}
+				used[i] = true
+				fillGroup([]int{i}, i+1, size, remaining)
+				used[i] = false
+				return
+			}
+		}
+	}
+
+	fillGroup = func(group []int, start int, size int, remaining int) {
+		if len(group) == size {
+			if _, ok := g.slowdowns(group); !ok {
+				return
+			}
+			current = append(current, group)
+			addGroup(remaining - size)
+			current = current[:len(current)-1]
+			return
+		}
+		for j := start; j < len(g.jobs); j++ {
+			if used[j] {
+				continue
+			}
+			used[j] = true
+			fillGroup(append(append([]int{}, group...), j), j+1, size, remaining)
+			used[j] = false
+		}
+	}
+
+	addGroup(len(g.jobs))
+
+	return best
+}
+
+// greedy construction followed by swapping jobs between groups as long as the schedule improves
+func (g *graphT) heuristic() [][]int {
+	used := make([]bool, len(g.jobs))
+	var groups [][]int
+
+	for i := range g.jobs {
+		if used[i] {
+			continue
+		}
+		used[i] = true
+		group := []int{i}
+		for len(group) < g.opts.Slots {
+			bestJob := -1
+			bestCost := math.Inf(1)
+			for j := range g.jobs {
+				if used[j] {
+					continue
+				}
+				if c := g.cost([][]int{append(append([]int{}, group...), j)}); c < bestCost {
+					bestJob, bestCost = j, c
+				}
+			}
+			if bestJob == -1 {
+				break
+			}
+			used[bestJob] = true
+			group = append(group, bestJob)
+		}
+		groups = append(groups, group)
+	}
+
+	cost := g.cost(groups)
+	for improved := true; improved; {
+		improved = false
+		for a := range groups {
+			for b := a + 1; b < len(groups); b++ {
+				for x := range groups[a] {
+					for y := range groups[b] {
+						groups[a][x], groups[b][y] = groups[b][y], groups[a][x]
+						if c := g.cost(groups); c < cost {
+							cost = c
+							improved = true
+							continue
+						}
+						groups[a][x], groups[b][y] = groups[b][y], groups[a][x]
+					}
+				}
+			}
+		}
+	}
+
+	if math.IsInf(cost, 1) {
+		return nil
+	}
+	return groups
+}
+
+func (g *graphT) schedule(groups [][]int, exact bool) *ScheduleT {
+	ret := ScheduleT{Exact: exact}
+	sumNP, sumNP2, n := 0.0, 0.0, 0.0
+
+	for _, group := range groups {
+		var grp GroupT
+		grp.Slowdowns, _ = g.slowdowns(group)
+		for a, i := range group {
+			grp.Jobs = append(grp.Jobs, g.jobs[i])
+			bits := stats.NoCATMask
+			if len(group) == 2 {
+				bits = g.edges[i][group[(a+1)%2]].CATBits[0]
+			}
+			grp.CATBits = append(grp.CATBits, bits)
+
+			s := grp.Slowdowns[a]
+			sumNP += 1 / s
+			sumNP2 += 1 / (s * s)
+			ret.ANTT += s
+			ret.MaxSlowdown = math.Max(ret.MaxSlowdown, s)
+			n++
+		}
+		ret.Groups = append(ret.Groups, grp)
+	}
+
+	ret.STP = sumNP
+	ret.ANTT /= n
+	ret.Fairness = sumNP * sumNP / (n * sumNP2)
+
+	return &ret
+}
+
+func copyGroups(groups [][]int) [][]int {
+	ret := make([][]int, len(groups))
+	for i := range groups {
+		ret[i] = append([]int{}, groups[i]...)
+	}
+	return ret
+}
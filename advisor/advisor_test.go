@@ -0,0 +1,59 @@
+package advisor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jbreitbart/coBench/stats"
+)
+
+func runs(seconds float64) []stats.DataPerRun {
+	r := make([]stats.DataPerRun, 3)
+	for i := range r {
+		r[i].Runtime = time.Duration(seconds * float64(time.Second))
+	}
+	return r
+}
+
+func TestAdvise(t *testing.T) {
+	var data stats.StatsT
+	apps := []string{"a", "b", "c", "d"}
+	for _, app := range apps {
+		data.AddReferenceRuntime(app, runs(1))
+	}
+	slowdowns := map[[2]string]float64{
+		{"a", "b"}: 2, {"c", "d"}: 2,
+		{"a", "c"}: 1.1, {"b", "d"}: 1.1,
+		{"a", "d"}: 1.5, {"b", "c"}: 1.5,
+	}
+	for pair, s := range slowdowns {
+		data.AddCoSchedRuntime(pair[0], pair[1], runs(s))
+		data.AddCoSchedRuntime(pair[1], pair[0], runs(s))
+	}
+
+	for _, objective := range []string{ThroughputObjective, WorstCaseObjective} {
+		// exact and heuristic
+		for _, limit := range []int{DefaultExactLimit, 1} {
+			schedule, err := Advise(&data, apps, OptionsT{Slots: 2, Objective: objective, Metric: stats.RuntimeMetric, ExactLimit: limit})
+			if err != nil {
+				t.Fatalf("Advise failed: %v", err)
+			}
+			if len(schedule.Groups) != 2 || schedule.Exact != (limit == DefaultExactLimit) {
+				t.Fatalf("Unexpected schedule %v", schedule)
+			}
+			for _, g := range schedule.Groups {
+				pair := [2]string{g.Jobs[0], g.Jobs[1]}
+				if pair != [2]string{"a", "c"} && pair != [2]string{"b", "d"} {
+					t.Errorf("%v (limit %v): unexpected group %v", objective, limit, g.Jobs)
+				}
+			}
+			if schedule.MaxSlowdown < 1.1-1e-9 || schedule.MaxSlowdown > 1.1+1e-9 {
+				t.Errorf("Unexpected max slowdown %v", schedule.MaxSlowdown)
+			}
+		}
+	}
+
+	if _, err := Advise(&data, apps, OptionsT{Slots: 3, Objective: ThroughputObjective, CAT: true}); err == nil {
+		t.Errorf("CAT with 3 slots must be rejected")
+	}
+}
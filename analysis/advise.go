@@ -0,0 +1,64 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jbreitbart/coBench/advisor"
+	"github.com/jbreitbart/coBench/commands"
+	log "github.com/sirupsen/logrus"
+)
+
+// Recommends a schedule of the job mix, prints it and stores it as json.
+// jobList is a comma separated list of applications, empty means every application once.
+func (a *AnalyzerT) createSchedule(apps []string, jobList string, adviseOpts advisor.OptionsT) error {
+	jobs := apps
+	if jobList != "" {
+		jobs = nil
+		for _, name := range strings.Split(jobList, ",") {
+			app := findApp(apps, strings.TrimSpace(name))
+			if app == "" {
+				return fmt.Errorf("Unknown application %v in job mix", name)
+			}
+			jobs = append(jobs, app)
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"jobs":      len(jobs),
+		"slots":     adviseOpts.Slots,
+		"objective": adviseOpts.Objective,
+		"CAT":       adviseOpts.CAT,
+	}).Infoln("Computing co-scheduling recommendation")
+
+	schedule, err := advisor.Advise(a.data, jobs, adviseOpts)
+	if err != nil {
+		return fmt.Errorf("Could not compute a schedule: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Node\tApp\tCAT\tSlowdown")
+	for i, group := range schedule.Groups {
+		for k, job := range group.Jobs {
+			fmt.Fprintf(w, "%v\t%v\t%v\t%1.4f\n", i, commands.Pretty(job), catBitsToString(group.CATBits[k]), group.Slowdowns[k])
+		}
+	}
+	w.Flush()
+
+	log.WithFields(log.Fields{
+		"STP":          fmt.Sprintf("%1.4f", schedule.STP),
+		"ANTT":         fmt.Sprintf("%1.4f", schedule.ANTT),
+		"max slowdown": fmt.Sprintf("%1.4f", schedule.MaxSlowdown),
+		"fairness":     fmt.Sprintf("%1.4f", schedule.Fairness),
+		"exact":        schedule.Exact,
+	}).Infoln("Predicted metrics of the schedule")
+
+	j, err := json.MarshalIndent(schedule, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Error while creating json: %v", err)
+	}
+	return a.writeFile("schedule.json", j)
+}
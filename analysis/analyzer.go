@@ -0,0 +1,286 @@
+package analysis
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/jbreitbart/coBench/advisor"
+	"github.com/jbreitbart/coBench/commands"
+	"github.com/jbreitbart/coBench/plot"
+	"github.com/jbreitbart/coBench/sensitivity"
+	"github.com/jbreitbart/coBench/stats"
+	log "github.com/sirupsen/logrus"
+)
+
+// OptionsT configures the analysis of a result file
+type OptionsT struct {
+	// metric used for the plots and slowdowns
+	Metric string
+	// plot the median with the interquartile range instead of mean and standard deviation
+	Median bool
+	// if not nil the slowdown compared to the baseline is plotted
+	Baseline *stats.BaselineT
+	// backends used for the CAT plots
+	PlotBackends []plot.BackendT
+
+	// create a slowdown matrix for every CAT configuration
+	MatrixCAT bool
+	// cells of the slowdown matrix with a wider relative confidence interval are marked as low confidence
+	MatrixCI float64
+
+	// partition objective, no partition is recommended if empty
+	Partition string
+	QoSApp    string
+	QoSTarget float64
+
+	// schedule objective, no schedule is recommended if empty
+	Advise    string
+	Jobs      string
+	Slots     int
+	AdviseCAT bool
+
+	// prediction model, no prediction is made if empty
+	Predict string
+	// perf counter used as cache or bandwidth pressure
+	Pressure string
+
+	// fit the cache sensitivity curves
+	Fit         bool
+	Sensitivity sensitivity.OptionsT
+
+	// directory of the written files, the current directory if empty
+	OutputDir string
+
+	// files written if not empty, relative names are in OutputDir
+	Report        string
+	ExportRuns    string
+	ExportConfigs string
+}
+
+// DefaultOptions returns the defaults of the analyze command line
+func DefaultOptions() OptionsT {
+	gnuplot, _ := plot.Get("gnuplot")
+	return OptionsT{
+		Metric:       stats.RuntimeMetric,
+		PlotBackends: []plot.BackendT{gnuplot},
+		MatrixCI:     0.05,
+		QoSTarget:    1.1,
+		Slots:        2,
+		Sensitivity:  sensitivity.OptionsT{Threshold: sensitivity.DefaultThreshold},
+	}
+}
+
+// AnalyzerT writes the analysis of a result file
+type AnalyzerT struct {
+	data *stats.StatsT
+	opts OptionsT
+}
+
+// New returns an analyzer of data with the options. The metric must be defined by the metric rules of data.
+func New(data *stats.StatsT, o OptionsT) (*AnalyzerT, error) {
+	if o.Metric != stats.RuntimeMetric && data.MetricRule(o.Metric) == nil {
+		return nil, fmt.Errorf("Metric %v is not defined in the metric rules", o.Metric)
+	}
+	return &AnalyzerT{data: data, opts: o}, nil
+}
+
+// Load reads the result file. If metricRules is not empty, the metric rules are replaced by the rules in this file and
+// the metrics are recomputed.
+func Load(inputFile string, metricRules string) (*stats.StatsT, error) {
+	data, err := stats.ReadDataset(inputFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if metricRules != "" {
+		rules, err := stats.ReadMetricRules(metricRules)
+		if err != nil {
+			return nil, fmt.Errorf("Could not read metric rules: %v", err)
+		}
+		if err = data.SetMetricRules(rules); err != nil {
+			return nil, fmt.Errorf("Invalid metric rules: %v", err)
+		}
+		data.RecomputeMetrics()
+	}
+	return data, nil
+}
+
+// Run writes the dat files, plots and recommendations of the result file to the output directory. inputFile is
+// only shown in the report.
+func (a *AnalyzerT) Run(inputFile string) error {
+	if err := a.createOutputDir(); err != nil {
+		return err
+	}
+
+	apps := a.data.Commandline.Commands
+
+	log.Infoln("Found data for the following applications:")
+	for i, app := range apps {
+		log.WithFields(log.Fields{
+			"app": app,
+		}).Infof("%v", i)
+	}
+
+	if err := a.createExports(a.opts.ExportRuns, a.opts.ExportConfigs); err != nil {
+		return err
+	}
+
+	indvApps := commands.GenerateIndv(apps)
+	rawDatFiles, rawApps, err := a.createRawDatFiles(indvApps)
+	if err != nil {
+		return err
+	}
+	if err = a.writeGNUPlotRawFile(rawApps, rawDatFiles); err != nil {
+		return err
+	}
+
+	if err = a.createIndvCATDatFiles(indvApps); err != nil {
+		return err
+	}
+	var fits map[string]fitCurvesT
+	if a.opts.Fit {
+		if fits, err = a.createCacheSensitivity(indvApps, a.opts.Sensitivity, a.opts.Pressure); err != nil {
+			return err
+		}
+	}
+	if err = a.writeCATIndvPlots(indvApps, fits); err != nil {
+		return err
+	}
+
+	pairs := commands.GeneratePairs(apps)
+	a.printCoSchedSlowdowns(pairs)
+	if err = a.createPairMetricsFile(pairs); err != nil {
+		return err
+	}
+
+	if a.opts.Partition != "" {
+		if err = a.createPartitionRecommendation(indvApps, pairs, a.opts.Partition, a.opts.QoSApp, a.opts.QoSTarget); err != nil {
+			return err
+		}
+	}
+
+	if a.opts.Advise != "" {
+		if err = a.createSchedule(indvApps, a.opts.Jobs, advisor.OptionsT{Slots: a.opts.Slots, Objective: a.opts.Advise, CAT: a.opts.AdviseCAT, Metric: a.opts.Metric}); err != nil {
+			return err
+		}
+	}
+
+	if a.opts.Predict != "" {
+		if err = a.createPrediction(indvApps, a.opts.Predict, a.opts.Pressure); err != nil {
+			return err
+		}
+	}
+
+	matrixFiles, err := a.createSlowdownMatrixFiles(indvApps, a.opts.MatrixCAT, a.opts.MatrixCI)
+	if err != nil {
+		return err
+	}
+	if err = a.writeGNUPlotSlowdownMatrixFile(indvApps, matrixFiles); err != nil {
+		return err
+	}
+
+	for _, paired := range []bool{false, true} {
+		if err = a.createCoSchedCATDatFiles(pairs, paired); err != nil {
+			return err
+		}
+		if err = a.writeCATCoSchedPlots(pairs, paired); err != nil {
+			return err
+		}
+	}
+
+	if a.opts.Report != "" {
+		if err = a.createReport(a.opts.Report, inputFile, indvApps, pairs, a.opts.MatrixCI); err != nil {
+			return err
+		}
+	}
+
+	perfSeriesFiles, err := a.createIndvPerfSeriesDatFiles(indvApps)
+	if err != nil {
+		return err
+	}
+	coSchedPerfSeriesFiles, err := a.createCoSchedPerfSeriesDatFiles(pairs)
+	if err != nil {
+		return err
+	}
+	return a.writeGNUPlotPerfSeriesFile(append(perfSeriesFiles, coSchedPerfSeriesFiles...))
+}
+
+// Report only writes the HTML report of the result file to the output directory. inputFile is shown in the
+// report.
+func (a *AnalyzerT) Report(inputFile string) error {
+	if err := a.createOutputDir(); err != nil {
+		return err
+	}
+
+	apps := a.data.Commandline.Commands
+	return a.createReport(a.opts.Report, inputFile, commands.GenerateIndv(apps), commands.GeneratePairs(apps), a.opts.MatrixCI)
+}
+
+func (a *AnalyzerT) createOutputDir() error {
+	if a.opts.OutputDir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(a.opts.OutputDir, 0755); err != nil {
+		return fmt.Errorf("Cannot create output directory: %v", err)
+	}
+	return nil
+}
+
+// path of a file written by the analysis. Relative names are in the output directory.
+func (a *AnalyzerT) path(filename string) string {
+	if filepath.IsAbs(filename) {
+		return filename
+	}
+	return filepath.Join(a.opts.OutputDir, filename)
+}
+
+// writes a file to the output directory
+func (a *AnalyzerT) writeFile(filename string, content []byte) error {
+	if err := ioutil.WriteFile(a.path(filename), content, 0644); err != nil {
+		return fmt.Errorf("Error while write file %v: %v", filename, err)
+	}
+	return nil
+}
+
+func (a *AnalyzerT) printCoSchedSlowdowns(pairs [][2]string) {
+	log.WithField("metric", a.opts.Metric).Infoln("Slowdown of co-scheduled applications")
+
+	ref := stats.BaselineT{Kind: stats.ReferenceBaseline}
+	for _, pair := range pairs {
+		for i := range pair {
+			app, cosched := pair[i], pair[(i+1)%2]
+			n := a.data.CoSchedRuntimesNormalized(app, cosched, ref, a.opts.Metric)
+			if n == nil {
+				continue
+			}
+			log.WithFields(log.Fields{
+				"co-scheduled": commands.Pretty(cosched),
+				"slowdown":     fmt.Sprintf("%1.6f", n.Mean),
+				"error":        fmt.Sprintf("%1.6f", n.Error),
+				"median":       fmt.Sprintf("%1.6f", n.Median),
+			}).Infof("%v", commands.Pretty(app))
+		}
+	}
+}
+
+func (a *AnalyzerT) metricName() string {
+	if a.opts.Baseline != nil {
+		return "slowdown"
+	}
+	if a.opts.Metric == stats.RuntimeMetric {
+		return "runtime"
+	}
+	return a.opts.Metric
+}
+
+func (a *AnalyzerT) metricLabel() string {
+	if a.opts.Baseline != nil {
+		return "Slowdown (" + a.opts.Metric + ")"
+	}
+	if a.opts.Metric == stats.RuntimeMetric {
+		return "Runtime (s)"
+	}
+	return a.opts.Metric + " (" + a.data.MetricUnit(a.opts.Metric) + ")"
+}
@@ -0,0 +1,245 @@
+package analysis
+
+import (
+	"math"
+	"strings"
+
+	"sort"
+	"strconv"
+
+	"github.com/jbreitbart/coBench/commands"
+	"github.com/jbreitbart/coBench/stats"
+	log "github.com/sirupsen/logrus"
+)
+
+func sortedKeys(r *map[int]stats.RuntimeT) []int {
+	var sortedKeys []int
+	for k := range *r {
+		sortedKeys = append(sortedKeys, k)
+	}
+	sort.Slice(sortedKeys[:], func(i, j int) bool {
+		return sortedKeys[i] < sortedKeys[j]
+	})
+
+	return sortedKeys
+}
+
+func (a *AnalyzerT) createCoSchedCATDatFiles(pairs [][2]string, matchpairs bool) error {
+	log.WithField("paired", matchpairs).Infoln("Creating dat files for co-scheduling CAT runs")
+
+	for _, pair := range pairs {
+		r0 := a.data.CoSchedCATRuntimes(pair[0], pair[1])
+		r1 := a.data.CoSchedCATRuntimes(pair[1], pair[0])
+
+		if r0 == nil && r1 == nil {
+			log.WithFields(log.Fields{
+				"app0": pair[0],
+				"app1": pair[1],
+			}).Infoln("No CAT co-scheduling data")
+			continue
+		}
+
+		if r0 == nil || r1 == nil {
+			log.WithFields(log.Fields{
+				"app0": pair[0],
+				"app1": pair[1],
+			}).Errorln("CAT co-scheduling data inconsistent")
+			continue
+		}
+
+		out := "# 0: " + pair[0] + "\n"
+		out += "# co-scheduled with \n"
+		out += "# 1: " + pair[1] + "\n"
+		if matchpairs {
+			out += "# L3 limit correct only for 0, 1 uses rest\n"
+		}
+
+		sortedKeys0 := sortedKeys(r0)
+		sortedKeys1 := sortedKeys(r1)
+		arbRun := (*r0)[sortedKeys0[0]]
+
+		out += "# L3(0) " + a.metricName() + "(0) " + a.metricErrorNames("(0)")
+		for _, temp := range extractPerfData(&arbRun) {
+			out += temp.Name + "(0) "
+			out += "Std.Dev." + temp.Name + "(0) "
+		}
+
+		out += a.metricName() + "(1) " + a.metricErrorNames("(1)")
+		for _, temp := range extractPerfData(&arbRun) {
+			out += temp.Name + "(1) "
+			out += "Std.Dev." + temp.Name + "(1) "
+		}
+		out += "\n"
+
+		for i := range sortedKeys0 {
+			k0 := sortedKeys0[i]
+			k1 := sortedKeys1[i]
+			if matchpairs {
+				k1 = sortedKeys1[len(sortedKeys1)-i-1]
+			}
+			v0, v1 := (*r0)[k0], (*r1)[k1]
+			out += a.coSchedRuntimeToString(a.cacheAxisToString(k0), pair[0], &v0, pair[1], &v1, extractPerfData(&v0), extractPerfData(&v1))
+		}
+
+		ref0 := a.data.CoSchedRuntimes(pair[0], pair[1])
+		ref1 := a.data.CoSchedRuntimes(pair[1], pair[0])
+		out += referenceBlockHeader
+		out += a.coSchedRuntimeToString(a.referenceDatX(r0), pair[0], ref0, pair[1], ref1, extractPerfData(ref0), extractPerfData(ref1))
+
+		if err := a.writeFile(coSchedCATDatFilename(pair[0], pair[1], matchpairs), []byte(out)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *AnalyzerT) createIndvCATDatFiles(apps []string) error {
+	log.Println("Creating dat files for individual CAT runs.")
+
+	for _, app := range apps {
+		catRuntime := a.data.CATRuntimes(app)
+		if catRuntime == nil {
+			continue
+		}
+
+		out := "# " + app + "\n"
+
+		sortedKeys := sortedKeys(catRuntime)
+		arbRun := (*catRuntime)[sortedKeys[0]]
+
+		out += "# L3 " + a.metricName() + " " + a.metricErrorNames("")
+		for _, temp := range extractPerfData(&arbRun) {
+			out += temp.Name + " "
+			out += "Std.Dev." + temp.Name + " "
+		}
+		out += "\n"
+
+		for _, k := range sortedKeys {
+			log.WithField("app", app).WithField("cat", k).Debugln("Currently analysing")
+			v := (*catRuntime)[k]
+			out += a.coSchedRuntimeToString(a.cacheAxisToString(k), app, &v, "", nil, extractPerfData(&v), nil)
+		}
+
+		log.WithField("app", app).WithField("cat", "no cat").Debugln("Currently analysing")
+		ref := a.data.ReferenceRuntime(app)
+		out += referenceBlockHeader
+		out += a.coSchedRuntimeToString(a.referenceDatX(catRuntime), app, ref, "", nil, extractPerfData(ref), nil)
+
+		if err := a.writeFile(indvCATDatFilename(app), []byte(out)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// The runs without CAT are stored in a second block (gnuplot index 1) of the dat files, at the x coordinate of
+// the 'no CAT' tic.
+const referenceBlockHeader = "\n\n# no CAT (unpartitioned)\n"
+
+// referenceX of the CAT runs r as x coordinate of the dat files
+func (a *AnalyzerT) referenceDatX(r *map[int]stats.RuntimeT) string {
+	return strconv.FormatFloat(a.referenceX(r), 'E', -1, 64)
+}
+
+// size of the L3 cache in MB assigned by CAT bits, NaN if the hardware is unknown
+func (a *AnalyzerT) catBitsToMB(bits float64) float64 {
+	return bits * a.data.Hardware.MBPerCATBit()
+}
+
+// x coordinate of CAT bits in the plots. Falls back to the number of bits if the L3 size is unknown.
+func (a *AnalyzerT) cacheAxis(bits float64) float64 {
+	if mb := a.catBitsToMB(bits); !math.IsNaN(mb) {
+		return mb
+	}
+	return bits
+}
+
+func (a *AnalyzerT) cacheAxisToString(bits int) string {
+	return strconv.FormatFloat(a.cacheAxis(float64(bits)), 'E', -1, 64)
+}
+
+func (a *AnalyzerT) cacheAxisLabel() string {
+	if math.IsNaN(a.data.Hardware.MBPerCATBit()) {
+		return "L3 Cache (CAT bits)"
+	}
+	return "L3 Cache (MB)"
+}
+
+func (a *AnalyzerT) coSchedRuntimeToString(x string, app0 string, ref0 *stats.RuntimeT, app1 string, ref1 *stats.RuntimeT, perf0, perf1 []perfDataT) string {
+	out := x + " "
+	out += a.metricToString(app0, ref0)
+	for _, p := range perf0 {
+		out += " " + strconv.FormatFloat(p.Mean, 'E', -1, 64) + " " + strconv.FormatFloat(p.Stddev, 'E', -1, 64)
+	}
+
+	if ref1 != nil {
+		out += " "
+		out += a.metricToString(app1, ref1)
+		for _, p := range perf1 {
+			out += " " + strconv.FormatFloat(p.Mean, 'E', -1, 64) + " " + strconv.FormatFloat(p.Stddev, 'E', -1, 64)
+		}
+	}
+	out += "\n"
+	return out
+}
+
+// mean and standard deviation or median, first and third quartile depending on -median.
+// If a baseline is selected the slowdown of app compared to the baseline is returned.
+// value of the metric followed by its error as plotted: mean and error or stddev, or median, P25 and P75
+func (a *AnalyzerT) metricValues(app string, r *stats.RuntimeT) []float64 {
+	var values []float64
+	if a.opts.Baseline != nil {
+		n := a.data.Normalize(r, a.data.Baseline(app, *a.opts.Baseline, a.opts.Metric), a.opts.Metric)
+		switch {
+		case n == nil && a.opts.Median:
+			values = []float64{math.NaN(), math.NaN(), math.NaN()}
+		case n == nil:
+			values = []float64{math.NaN(), math.NaN()}
+		case a.opts.Median:
+			values = []float64{n.Median, n.P25, n.P75}
+		default:
+			values = []float64{n.Mean, n.Error}
+		}
+	} else if a.opts.Median {
+		m, p25, p75 := r.MetricQuartiles(a.opts.Metric)
+		values = []float64{m, p25, p75}
+	} else {
+		mean, stddev := r.MetricStats(a.opts.Metric)
+		values = []float64{mean, stddev}
+	}
+	return values
+}
+
+func (a *AnalyzerT) metricToString(app string, r *stats.RuntimeT) string {
+	var out []string
+	for _, v := range a.metricValues(app, r) {
+		out = append(out, strconv.FormatFloat(v, 'E', -1, 64))
+	}
+	return strings.Join(out, " ")
+}
+
+func (a *AnalyzerT) metricErrorNames(suffix string) string {
+	if a.opts.Baseline != nil && !a.opts.Median {
+		return "Error" + suffix + " "
+	}
+	if a.opts.Median {
+		return "P25" + suffix + " P75" + suffix + " "
+	}
+	return "Std.Dev." + suffix + " "
+}
+
+func indvCATDatFilename(app string) string {
+	// TODO check if filename already in use
+	return commands.Pretty(app) + "-cat.dat"
+}
+
+func coSchedCATDatFilename(app0 string, app1 string, matchpairs bool) string {
+	// TODO check if filename already in use
+
+	ret := commands.Pretty(app0) + "-" + commands.Pretty(app1) + "-cosched-cat"
+	if matchpairs {
+		ret += "-paired"
+	}
+	ret += ".dat"
+	return ret
+}
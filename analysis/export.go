@@ -0,0 +1,45 @@
+package analysis
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// tab separated for .tsv files, comma separated otherwise
+func exportSeparator(filename string) rune {
+	if filepath.Ext(filename) == ".tsv" {
+		return '\t'
+	}
+	return ','
+}
+
+func (a *AnalyzerT) createExport(filename string, export func(w io.Writer, comma rune) error) error {
+	log.WithField("file", filename).Infoln("Exporting measurements")
+
+	file, err := os.Create(a.path(filename))
+	if err != nil {
+		return fmt.Errorf("Cannot create export file: %v", err)
+	}
+	defer file.Close()
+
+	if err = export(file, exportSeparator(filename)); err != nil {
+		return fmt.Errorf("Error while write export file %v: %v", filename, err)
+	}
+	return nil
+}
+
+func (a *AnalyzerT) createExports(runsFile string, configsFile string) error {
+	if runsFile != "" {
+		if err := a.createExport(runsFile, a.data.ExportRuns); err != nil {
+			return err
+		}
+	}
+	if configsFile != "" {
+		return a.createExport(configsFile, a.data.ExportConfigs)
+	}
+	return nil
+}
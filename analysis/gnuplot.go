@@ -0,0 +1,41 @@
+package analysis
+
+import "github.com/jbreitbart/coBench/stats"
+
+func gnuplotHeader() string {
+	ret := "set terminal pdf\n"
+	ret += "set yrange [0:*]\n"
+	ret += "set key right bottom\n"
+	ret += "unset x2tics\n"
+	ret += "unset y2tics\n"
+	ret += "set border 3\n"
+	//ret += "set ylabel 'Runtime (s)'\n"
+
+	ret += "set style line 1 lc rgb '#0060ad' lt 1 lw 2 pt 7 ps 0.1   # --- blue\n"
+	ret += "set style line 2 lc rgb '#dd181f' lt 1 lw 2 pt 5 ps 0.1   # --- red\n"
+	ret += "set pointintervalbox 3\n"
+	//ret += "shadecolor = '#80E0A080'\n"
+
+	return ret
+}
+
+// x position of the runs without CAT in plots of the CAT runs r. Positioned at the whole cache.
+func (a *AnalyzerT) referenceX(r *map[int]stats.RuntimeT) float64 {
+	bits := 0
+	if r != nil && a.data.Hardware.CATBits != 0 {
+		bits = a.data.WholeCacheBits(r)
+	}
+	return a.cacheAxis(float64(bits))
+}
+
+// labels the position of the runs without CAT on the x axis
+func gnuplotReferenceTic(x string) string {
+	return "unset xtics\nset xtics\nset xtics add ('no CAT' " + x + ")\n"
+}
+
+func (a *AnalyzerT) metricSymbol() string {
+	if a.opts.Median {
+		return "x̃"
+	}
+	return "Ø"
+}
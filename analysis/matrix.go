@@ -0,0 +1,147 @@
+package analysis
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/jbreitbart/coBench/commands"
+	"github.com/jbreitbart/coBench/plot"
+	"github.com/jbreitbart/coBench/stats"
+	log "github.com/sirupsen/logrus"
+)
+
+type slowdownMatrixFileT struct {
+	Filename string
+	Title    string
+}
+
+func slowdownMatrixBasename(catBits int) string {
+	if catBits == 0 {
+		return "slowdown-matrix"
+	}
+	return "slowdown-matrix-cat" + strconv.Itoa(catBits)
+}
+
+func slowdownMatrixToCSV(apps []string, matrix [][]stats.SlowdownCellT) string {
+	out := "app"
+	for _, app := range apps {
+		out += "," + commands.Pretty(app)
+	}
+	out += "\n"
+
+	for i, app := range apps {
+		out += commands.Pretty(app)
+		for _, cell := range matrix[i] {
+			out += ","
+			if cell.Valid {
+				out += strconv.FormatFloat(cell.Slowdown, 'E', -1, 64)
+			}
+		}
+		out += "\n"
+	}
+	return out
+}
+
+func slowdownMatrixToDat(apps []string, matrix [][]stats.SlowdownCellT) string {
+	out := "# Slowdown of the row app co-scheduled with the column app\n"
+	for i, app := range apps {
+		out += "# " + strconv.Itoa(i) + ": " + app + "\n"
+	}
+	out += "# Row Column Slowdown LowConfidence CI-low CI-high\n"
+
+	for i := range apps {
+		for j, cell := range matrix[i] {
+			out += strconv.Itoa(i) + " " + strconv.Itoa(j)
+			if !cell.Valid {
+				out += " NaN 1 NaN NaN\n"
+				continue
+			}
+			lowConfidence := "0"
+			if cell.LowConfidence {
+				lowConfidence = "1"
+			}
+			out += " " + strconv.FormatFloat(cell.Slowdown, 'E', -1, 64) + " " + lowConfidence
+			out += " " + strconv.FormatFloat(cell.CI[0], 'E', -1, 64) + " " + strconv.FormatFloat(cell.CI[1], 'E', -1, 64) + "\n"
+		}
+		// new row of the image
+		out += "\n"
+	}
+	return out
+}
+
+// Writes the slowdown matrix without CAT and, if requested, for every CAT configuration as csv and dat file
+func (a *AnalyzerT) createSlowdownMatrixFiles(apps []string, withCAT bool, maxRelCI float64) ([]slowdownMatrixFileT, error) {
+	log.WithField("CAT", withCAT).Infoln("Creating slowdown matrix files")
+
+	configs := []int{0}
+	if withCAT {
+		configs = append(configs, a.data.CoSchedCATBits(apps)...)
+	}
+
+	ret := make([]slowdownMatrixFileT, 0)
+	for _, catBits := range configs {
+		matrix := a.data.SlowdownMatrix(apps, catBits, a.opts.Metric, stats.DefaultCILevel, maxRelCI)
+
+		valid := false
+		for i := range matrix {
+			for _, cell := range matrix[i] {
+				valid = valid || cell.Valid
+			}
+		}
+		if !valid {
+			continue
+		}
+
+		basename := slowdownMatrixBasename(catBits)
+		for ending, content := range map[string]string{".csv": slowdownMatrixToCSV(apps, matrix), ".dat": slowdownMatrixToDat(apps, matrix)} {
+			if err := a.writeFile(basename+ending, []byte(content)); err != nil {
+				return nil, err
+			}
+		}
+
+		title := "Slowdown without CAT"
+		if catBits != 0 {
+			title = "Slowdown with " + strconv.Itoa(catBits) + " CAT bits for the row app"
+		}
+		ret = append(ret, slowdownMatrixFileT{Filename: basename + ".dat", Title: title})
+	}
+
+	return ret, nil
+}
+
+func (a *AnalyzerT) writeGNUPlotSlowdownMatrixFile(apps []string, files []slowdownMatrixFileT) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	log.Infoln("Creating plot file for the slowdown matrix")
+
+	var tics []string
+	for i, app := range apps {
+		tics = append(tics, "'"+plot.GnuplotEscape(commands.Pretty(app))+"' "+strconv.Itoa(i))
+	}
+	n := strconv.Itoa(len(apps))
+
+	var ret string
+	ret += "set output 'slowdown-matrix.pdf'\n"
+	ret += "set terminal pdf\n"
+	ret += "unset key\n"
+	ret += "set border 3\n"
+	ret += "set xtics (" + strings.Join(tics, ", ") + ") rotate by 45 right\n"
+	ret += "set ytics (" + strings.Join(tics, ", ") + ")\n"
+	ret += "set xrange [-0.5:" + n + "-0.5]\n"
+	ret += "set yrange [" + n + "-0.5:-0.5]\n"
+	ret += "set xlabel 'co-scheduled with'\n"
+	ret += "set ylabel 'slowdown of'\n"
+	ret += "set cblabel 'Slowdown'\n"
+	ret += "set palette defined (0 '#ffffff', 1 '#0060ad', 2 '#dd181f')\n"
+	ret += "set label 1 '* low confidence' at screen 0.02, screen 0.03\n"
+
+	for _, f := range files {
+		ret += "set title '" + f.Title + "'\n"
+		ret += "plot '" + f.Filename + "' using 2:1:3 with image, "
+		ret += "'' using 2:1:($3 != $3 ? '' : sprintf($4 == 1 ? '%.2f*' : '%.2f', $3)) with labels\n"
+	}
+
+	return a.writeFile("slowdown-matrix.plot", []byte(ret))
+}
@@ -0,0 +1,74 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/jbreitbart/coBench/commands"
+	"github.com/jbreitbart/coBench/stats"
+	log "github.com/sirupsen/logrus"
+)
+
+func pairMetricsToString(app0, app1 string, CATBits int, m *stats.PairMetricsT) string {
+	out := commands.Pretty(app0) + " " + commands.Pretty(app1) + " " + strconv.Itoa(CATBits)
+	for _, v := range []float64{m.Slowdowns[0], m.Slowdowns[1], m.STP, m.WeightedSpeedup, m.ANTT, m.Fairness, m.Makespan, m.Sequential, m.MakespanRatio} {
+		out += " " + strconv.FormatFloat(v, 'E', -1, 64)
+	}
+	return out + "\n"
+}
+
+func logPairMetrics(app0, app1 string, CATBits int, m *stats.PairMetricsT) {
+	log.WithFields(log.Fields{
+		"CAT bits (0)":   CATBits,
+		"slowdown (0)":   fmt.Sprintf("%1.4f", m.Slowdowns[0]),
+		"slowdown (1)":   fmt.Sprintf("%1.4f", m.Slowdowns[1]),
+		"STP":            fmt.Sprintf("%1.4f", m.STP),
+		"WS":             fmt.Sprintf("%1.4f", m.WeightedSpeedup),
+		"ANTT":           fmt.Sprintf("%1.4f", m.ANTT),
+		"fairness":       fmt.Sprintf("%1.4f", m.Fairness),
+		"makespan ratio": fmt.Sprintf("%1.4f", m.MakespanRatio),
+	}).Infof("%v + %v", commands.Pretty(app0), commands.Pretty(app1))
+}
+
+// Prints the pair metrics of every co-scheduled pair and CAT configuration and stores them in pair-metrics.dat
+func (a *AnalyzerT) createPairMetricsFile(pairs [][2]string) error {
+	log.WithField("metric", a.opts.Metric).Infoln("Pair metrics of co-scheduled applications. CAT bits 0 means no CAT")
+
+	out := "# App(0) App(1) CATBits(0) Slowdown(0) Slowdown(1) STP WeightedSpeedup ANTT Fairness Makespan(s) Sequential(s) MakespanRatio\n"
+	found := false
+
+	for _, pair := range pairs {
+		if m := a.data.CoSchedPairMetrics(pair[0], pair[1], a.opts.Metric); m != nil {
+			logPairMetrics(pair[0], pair[1], stats.NoCATMask, m)
+			out += pairMetricsToString(pair[0], pair[1], stats.NoCATMask, m)
+			found = true
+		}
+
+		cat := a.data.CoSchedCATPairMetrics(pair[0], pair[1], a.opts.Metric)
+		if cat == nil {
+			continue
+		}
+		for _, k := range sortedPairMetricsKeys(cat) {
+			m := (*cat)[k]
+			logPairMetrics(pair[0], pair[1], k, &m)
+			out += pairMetricsToString(pair[0], pair[1], k, &m)
+			found = true
+		}
+	}
+
+	if !found {
+		return nil
+	}
+
+	return a.writeFile("pair-metrics.dat", []byte(out))
+}
+
+func sortedPairMetricsKeys(m *map[int]stats.PairMetricsT) []int {
+	var keys []int
+	for k := range *m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
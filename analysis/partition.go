@@ -0,0 +1,118 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/jbreitbart/coBench/commands"
+	"github.com/jbreitbart/coBench/stats"
+	log "github.com/sirupsen/logrus"
+)
+
+// resolves the pretty name of an application to the command stored in the result file
+func findApp(apps []string, name string) string {
+	for _, app := range apps {
+		if app == name || commands.Pretty(app) == name {
+			return app
+		}
+	}
+	return ""
+}
+
+func catBitsToString(bits int) string {
+	if bits == stats.NoCATMask {
+		return "shared"
+	}
+	return fmt.Sprintf("%d", bits)
+}
+
+// ConfigToString returns the co-scheduled application and the CAT bits or cgroup setting of c as shown in tables
+func ConfigToString(c stats.ConfigT) (string, string) {
+	cosched := "-"
+	if c.CoSched != "" {
+		cosched = commands.Pretty(c.CoSched)
+	}
+	if c.Cgroup != "" {
+		return cosched, "cgroup " + c.Cgroup
+	}
+	if c.CATBits == stats.NoCATMask {
+		return cosched, "no CAT"
+	}
+	return cosched, catBitsToString(c.CATBits)
+}
+
+func ciToString(ci [2]float64) string {
+	if ci[0] == 0 && ci[1] == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("[%1.4f, %1.4f]", ci[0], ci[1])
+}
+
+func printPartitionTable(recs []stats.PartitionRecommendationT) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "App(0)\tApp(1)\tCAT(0)\tCAT(1)\tSlowdown(0)\tSlowdown(1)\tObjective\tObjective CI\tFeasible\tBest")
+
+	for _, rec := range recs {
+		for i := range rec.Candidates {
+			c := &rec.Candidates[i]
+			best := ""
+			if c == rec.Best {
+				best = "*"
+			}
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%1.4f\t%1.4f\t%1.4f\t%v\t%v\t%v\n",
+				commands.Pretty(rec.Apps[0]), commands.Pretty(rec.Apps[1]),
+				catBitsToString(c.CATBits[0]), catBitsToString(c.CATBits[1]),
+				c.Slowdowns[0], c.Slowdowns[1], c.Objective, ciToString(c.ObjectiveCI), c.Feasible, best)
+		}
+	}
+
+	w.Flush()
+}
+
+// Finds the best cache partition of every pair, prints a table and stores the recommendations as json
+func (a *AnalyzerT) createPartitionRecommendation(apps []string, pairs [][2]string, objectiveKind string, qosApp string, qosTarget float64) error {
+	if qosApp != "" {
+		app := findApp(apps, qosApp)
+		if app == "" {
+			return fmt.Errorf("Unknown QoS application %v", qosApp)
+		}
+		qosApp = app
+	}
+
+	objective, err := stats.ParseObjective(objectiveKind, qosApp, qosTarget)
+	if err != nil {
+		return fmt.Errorf("Invalid partition objective: %v", err)
+	}
+
+	log.WithField("objective", objectiveKind).Infoln("Searching the best cache partition of every pair")
+
+	recs := make([]stats.PartitionRecommendationT, 0)
+	for _, pair := range pairs {
+		rec := a.data.FindBestPartition(pair[0], pair[1], objective, a.opts.Metric, stats.DefaultCILevel)
+		if rec == nil {
+			continue
+		}
+		if rec.Best == nil {
+			log.WithFields(log.Fields{
+				"app0": pair[0],
+				"app1": pair[1],
+			}).Warnln("No partition meets the QoS target")
+		}
+		recs = append(recs, *rec)
+	}
+
+	if len(recs) == 0 {
+		log.Warnln("No co-scheduling data to find a cache partition")
+		return nil
+	}
+
+	printPartitionTable(recs)
+
+	j, err := json.MarshalIndent(recs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Error while creating json: %v", err)
+	}
+	return a.writeFile("partition-recommendation.json", j)
+}
@@ -0,0 +1,61 @@
+package analysis
+
+import (
+	"sort"
+
+	"github.com/jbreitbart/coBench/stats"
+	mstats "github.com/montanaflynn/stats"
+	log "github.com/sirupsen/logrus"
+)
+
+type perfDataT struct {
+	Name   string
+	Mean   float64
+	Stddev float64
+	Vari   float64
+}
+
+func extractPerfData(dat *stats.RuntimeT) []perfDataT {
+	coll := make(map[string][]float64)
+	for _, temp := range *dat.RawRuntimesByMask {
+		for _, runs := range temp {
+			if runs.Excluded() {
+				continue
+			}
+			for name, value := range stats.ParsePerfCounters(runs.Output) {
+				coll[name] = append(coll[name], value)
+			}
+		}
+	}
+	var sortedCollKeys []string
+	for k := range coll {
+		sortedCollKeys = append(sortedCollKeys, k)
+	}
+	sort.Slice(sortedCollKeys[:], func(i, j int) bool {
+		return sortedCollKeys[i] < sortedCollKeys[j]
+	})
+
+	// TODO verify that sortedCollKeys is identical for every call
+
+	perfData := make([]perfDataT, 0)
+	for _, v := range sortedCollKeys {
+		var p perfDataT
+		var err error
+		p.Name = v
+		p.Mean, err = mstats.Mean(coll[v])
+		if err != nil {
+			log.WithError(err).Errorln("Error while computing mean")
+		}
+		p.Stddev, err = mstats.StandardDeviation(coll[v])
+		if err != nil {
+			log.WithError(err).Errorln("Error while computing stddev")
+		}
+		p.Vari, err = mstats.Variance(coll[v])
+		if err != nil {
+			log.WithError(err).Errorln("Error while computing variance")
+		}
+		perfData = append(perfData, p)
+		log.WithField("compute perf", p).Debugln()
+	}
+	return perfData
+}
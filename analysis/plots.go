@@ -0,0 +1,211 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/jbreitbart/coBench/commands"
+	"github.com/jbreitbart/coBench/plot"
+	"github.com/jbreitbart/coBench/stats"
+	log "github.com/sirupsen/logrus"
+)
+
+// ParsePlotBackends returns the backends of a comma separated list of names
+func ParsePlotBackends(names string) ([]plot.BackendT, error) {
+	var ret []plot.BackendT
+	for _, name := range strings.Split(names, ",") {
+		b, err := plot.Get(strings.TrimSpace(name))
+		if err != nil {
+			return nil, fmt.Errorf("%v. Available: %v", err, strings.Join(plot.Backends(), ", "))
+		}
+		ret = append(ret, b)
+	}
+	return ret, nil
+}
+
+func (a *AnalyzerT) writeFigure(f *plot.FigureT) error {
+	if len(f.Charts) == 0 {
+		return nil
+	}
+	f.Dir = a.opts.OutputDir
+	for _, b := range a.opts.PlotBackends {
+		files, err := b.Write(f)
+		if err != nil {
+			return fmt.Errorf("Error while write plot with backend %v: %v", b.Name(), err)
+		}
+		log.WithField("backend", b.Name()).WithField("files", files).Debugln("Plot written")
+	}
+	return nil
+}
+
+// point of the metric as plotted in the dat files
+func (a *AnalyzerT) metricPoint(app string, r *stats.RuntimeT, x float64) plot.PointT {
+	v := a.metricValues(app, r)
+	if a.opts.Median {
+		return plot.PointT{X: x, Y: v[0], Low: v[1], High: v[2]}
+	}
+	return plot.PointT{X: x, Y: v[0], Low: v[0] - v[1], High: v[0] + v[1]}
+}
+
+// mean and standard deviation of the perf counter name
+func perfPoint(r *stats.RuntimeT, name string, x float64) plot.PointT {
+	for _, p := range extractPerfData(r) {
+		if p.Name == name {
+			return plot.PointT{X: x, Y: p.Mean, Low: p.Mean - p.Stddev, High: p.Mean + p.Stddev}
+		}
+	}
+	return plot.Point(x, math.NaN())
+}
+
+func perfNames(r *stats.RuntimeT) []string {
+	var ret []string
+	for _, p := range extractPerfData(r) {
+		ret = append(ret, p.Name)
+	}
+	return ret
+}
+
+func (a *AnalyzerT) metricSeriesName(app string) string {
+	return a.metricSymbol() + " " + a.metricName() + " (" + commands.Pretty(app) + ")"
+}
+
+// a chart of the metric and one chart per perf counter of app with every CAT configuration. fit may be nil.
+func (a *AnalyzerT) indvCATCharts(app string, fit *fitCurvesT) []plot.ChartT {
+	cat := a.data.CATRuntimes(app)
+	if cat == nil || len(*cat) == 0 {
+		return nil
+	}
+	keys := sortedKeys(cat)
+	x := a.referenceX(cat)
+	ref := a.data.ReferenceRuntime(app)
+
+	chart := func(ylabel string, name string, point func(r *stats.RuntimeT, x float64) plot.PointT) plot.ChartT {
+		series := plot.SeriesT{Name: name, Lines: true}
+		for _, k := range keys {
+			r := (*cat)[k]
+			series.Points = append(series.Points, point(&r, a.cacheAxis(float64(k))))
+		}
+		reference := plot.SeriesT{Name: "no CAT", Points: []plot.PointT{point(ref, x)}}
+		return plot.ChartT{
+			Title:     commands.Pretty(app),
+			XLabel:    a.cacheAxisLabel(),
+			YLabel:    ylabel,
+			Series:    []plot.SeriesT{series, reference},
+			XTics:     []plot.TicT{{Pos: x, Label: "no CAT"}},
+			YFromZero: true,
+		}
+	}
+
+	metricChart := chart(a.metricLabel(), a.metricSeriesName(app), func(r *stats.RuntimeT, x float64) plot.PointT {
+		return a.metricPoint(app, r, x)
+	})
+	if fit != nil {
+		metricChart.Series = append(metricChart.Series,
+			plot.SeriesT{Name: "piecewise linear fit", Points: fit.Piecewise, Lines: true, HidePoints: true, Dashed: true},
+			plot.SeriesT{Name: "power law fit", Points: fit.PowerLaw, Lines: true, HidePoints: true, Dashed: true})
+		metricChart.Markers = []float64{fit.Knee}
+	}
+	ret := []plot.ChartT{metricChart}
+
+	arbRun := (*cat)[keys[0]]
+	for _, name := range perfNames(&arbRun) {
+		name := name
+		ret = append(ret, chart(name, "Ø "+name+" ("+commands.Pretty(app)+")", func(r *stats.RuntimeT, x float64) plot.PointT {
+			return perfPoint(r, name, x)
+		}))
+	}
+	return ret
+}
+
+// charts of the pair co-scheduled with CAT. If paired, app0 with the fewest bits is shown with app1 with the most
+// bits, otherwise with the fewest bits as well.
+func (a *AnalyzerT) coSchedCATCharts(pair [2]string, paired bool) []plot.ChartT {
+	r0 := a.data.CoSchedCATRuntimes(pair[0], pair[1])
+	r1 := a.data.CoSchedCATRuntimes(pair[1], pair[0])
+	if r0 == nil || r1 == nil || len(*r0) != len(*r1) {
+		return nil
+	}
+
+	keys0, keys1 := sortedKeys(r0), sortedKeys(r1)
+	var splits [][2]int
+	for i := range keys0 {
+		if paired {
+			splits = append(splits, [2]int{keys0[i], keys1[len(keys1)-i-1]})
+		} else {
+			splits = append(splits, [2]int{keys0[i], keys1[i]})
+		}
+	}
+	x := a.referenceX(r0)
+
+	xlabel := a.cacheAxisLabel()
+	if paired {
+		xlabel += " for " + commands.Pretty(pair[0])
+	}
+
+	chart := func(ylabel string, name func(app string) string, point func(app string, r *stats.RuntimeT, x float64) plot.PointT) plot.ChartT {
+		var series [2]plot.SeriesT
+		var reference [2]plot.SeriesT
+		for i := range pair {
+			app, cosched := pair[i], pair[(i+1)%2]
+			series[i] = plot.SeriesT{Name: name(app), Lines: true}
+			for _, split := range splits {
+				series[i].Points = append(series[i].Points, point(app, a.data.Runtime(stats.ConfigT{App: app, CoSched: cosched, CATBits: split[i]}), a.cacheAxis(float64(split[0]))))
+			}
+			reference[i] = plot.SeriesT{Name: "no CAT (" + commands.Pretty(app) + ")"}
+			if r := a.data.CoSchedRuntimes(app, cosched); r != nil {
+				reference[i].Points = []plot.PointT{point(app, r, x)}
+			}
+		}
+		return plot.ChartT{
+			Title:     commands.Pretty(pair[0]) + " + " + commands.Pretty(pair[1]),
+			XLabel:    xlabel,
+			YLabel:    ylabel,
+			Series:    []plot.SeriesT{series[0], series[1], reference[0], reference[1]},
+			XTics:     []plot.TicT{{Pos: x, Label: "no CAT"}},
+			YFromZero: true,
+		}
+	}
+
+	ret := []plot.ChartT{chart(a.metricLabel(), a.metricSeriesName, a.metricPoint)}
+
+	arbRun := (*r0)[keys0[0]]
+	for _, name := range perfNames(&arbRun) {
+		name := name
+		ret = append(ret, chart(name, func(app string) string {
+			return "Ø " + name + " (" + commands.Pretty(app) + ")"
+		}, func(app string, r *stats.RuntimeT, x float64) plot.PointT {
+			return perfPoint(r, name, x)
+		}))
+	}
+	return ret
+}
+
+// creates indv-cat with the CAT runs of all apps
+func (a *AnalyzerT) writeCATIndvPlots(apps []string, fits map[string]fitCurvesT) error {
+	log.Infoln("Creating plots for individual CAT runs")
+
+	f := plot.FigureT{Name: "indv-cat"}
+	for _, app := range apps {
+		var fit *fitCurvesT
+		if curves, exists := fits[app]; exists {
+			fit = &curves
+		}
+		f.Charts = append(f.Charts, a.indvCATCharts(app, fit)...)
+	}
+	return a.writeFigure(&f)
+}
+
+// creates co-sched-cat or co-sched-cat-paired with the CAT runs of all pairs
+func (a *AnalyzerT) writeCATCoSchedPlots(pairs [][2]string, paired bool) error {
+	log.WithField("paired", paired).Infoln("Creating plots for co-scheduling CAT runs")
+
+	f := plot.FigureT{Name: "co-sched-cat"}
+	if paired {
+		f.Name = "co-sched-cat-paired"
+	}
+	for _, pair := range pairs {
+		f.Charts = append(f.Charts, a.coSchedCATCharts(pair, paired)...)
+	}
+	return a.writeFigure(&f)
+}
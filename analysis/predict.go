@@ -0,0 +1,57 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jbreitbart/coBench/commands"
+	"github.com/jbreitbart/coBench/predict"
+	log "github.com/sirupsen/logrus"
+)
+
+// Predicts the slowdown of every pair of apps, validates it against the measured pairs and stores it in prediction.csv
+func (a *AnalyzerT) createPrediction(apps []string, modelName string, pressureCounter string) error {
+	model := predict.Get(modelName)
+	if model == nil {
+		return fmt.Errorf("Unknown prediction model %v. Available: %v", modelName, strings.Join(predict.Models(), ", "))
+	}
+
+	log.WithFields(log.Fields{
+		"model":    modelName,
+		"pressure": pressureCounter,
+	}).Infoln("Predicting co-scheduling slowdowns")
+
+	vs, err := predict.PredictAll(a.data, model, apps, pressureCounter, a.opts.Metric)
+	if err != nil {
+		return fmt.Errorf("Prediction failed: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "App\tCo-scheduled\tPredicted\tMeasured\tError")
+	out := "app,cosched,predicted,measured,relerror\n"
+	for _, v := range vs {
+		measured, relErr := "-", "-"
+		if !math.IsNaN(v.Measured) {
+			measured = fmt.Sprintf("%1.4f", v.Measured)
+			relErr = fmt.Sprintf("%+2.2f%%", 100*v.RelError)
+		}
+		fmt.Fprintf(w, "%v\t%v\t%1.4f\t%v\t%v\n", commands.Pretty(v.App), commands.Pretty(v.CoSched), v.Predicted, measured, relErr)
+
+		out += commands.Pretty(v.App) + "," + commands.Pretty(v.CoSched) + "," + strconv.FormatFloat(v.Predicted, 'E', -1, 64) + ","
+		if !math.IsNaN(v.Measured) {
+			out += strconv.FormatFloat(v.Measured, 'E', -1, 64) + "," + strconv.FormatFloat(v.RelError, 'E', -1, 64)
+		} else {
+			out += ","
+		}
+		out += "\n"
+	}
+	w.Flush()
+
+	log.WithField("mean abs error", fmt.Sprintf("%2.2f%%", 100*predict.MeanAbsRelError(vs))).Infoln("Validation against measured pairs")
+
+	return a.writeFile("prediction.csv", []byte(out))
+}
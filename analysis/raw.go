@@ -0,0 +1,110 @@
+package analysis
+
+import (
+	"strconv"
+
+	"github.com/jbreitbart/coBench/commands"
+	"github.com/jbreitbart/coBench/plot"
+	"github.com/jbreitbart/coBench/stats"
+	log "github.com/sirupsen/logrus"
+)
+
+// values of the state column in the raw dat files
+const (
+	rawUsed    = 0
+	rawWarmup  = 1
+	rawOutlier = 2
+)
+
+func rawRunsToString(x string, dat *stats.RuntimeT) string {
+	var out string
+	for i, run := range sortedRuns(dat) {
+		state := rawUsed
+		if run.Warmup {
+			state = rawWarmup
+		} else if run.Outlier {
+			state = rawOutlier
+		}
+		out += x + " " + strconv.Itoa(i) + " "
+		out += strconv.FormatFloat(run.Runtime.Seconds(), 'E', -1, 64) + " " + strconv.Itoa(state) + "\n"
+	}
+	return out
+}
+
+// Returns the filenames of the dat files containing every individual run including warm-up runs and outliers
+func (a *AnalyzerT) createRawDatFiles(apps []string) ([]string, []string, error) {
+	log.Infoln("Creating dat files for all individual runs")
+
+	ret := make([]string, 0)
+	retApps := make([]string, 0)
+
+	for _, app := range apps {
+		ref := a.data.ReferenceRuntime(app)
+		if ref == nil || ref.RawRuntimesByMask == nil {
+			continue
+		}
+
+		out := "# " + app + "\n"
+		out += "# L3 Run Runtime State(0: used, 1: warm-up, 2: outlier)\n"
+
+		catRuntime := a.data.CATRuntimes(app)
+		if catRuntime != nil {
+			for _, k := range sortedKeys(catRuntime) {
+				v := (*catRuntime)[k]
+				out += rawRunsToString(a.cacheAxisToString(k), &v)
+			}
+		}
+		out += referenceBlockHeader
+		out += rawRunsToString(a.referenceDatX(catRuntime), ref)
+
+		filename := commands.Pretty(app) + "-raw.dat"
+		if err := a.writeFile(filename, []byte(out)); err != nil {
+			return nil, nil, err
+		}
+
+		ret = append(ret, filename)
+		retApps = append(retApps, app)
+	}
+
+	return ret, retApps, nil
+}
+
+// the runs without CAT (index 1) share the style of the CAT runs and are only identified by the x tic
+func rawTitle(index int, state string) string {
+	if index != 0 {
+		return ""
+	}
+	return state
+}
+
+func (a *AnalyzerT) writeGNUPlotRawFile(apps []string, filenames []string) error {
+	if len(filenames) == 0 {
+		return nil
+	}
+
+	log.Infoln("Creating plot file for all individual runs")
+
+	var ret string
+	ret += "set output 'raw.pdf'\n"
+	ret += gnuplotHeader()
+	ret += "set style line 3 lc rgb '#808080' lt 1 lw 2 pt 6 ps 0.5   # --- grey\n"
+	ret += "set xlabel '" + a.cacheAxisLabel() + "'\n"
+	ret += "set ylabel 'Runtime (s)'\n"
+
+	for i, app := range apps {
+		ret += gnuplotReferenceTic(a.referenceDatX(a.data.CATRuntimes(app)))
+		ret += "set title '" + plot.GnuplotEscape(commands.Pretty(app)) + "'\n"
+		ret += "plot '" + filenames[i] + "'"
+		for index := 0; index < 2; index++ {
+			if index != 0 {
+				ret += ", ''"
+			}
+			ret += " index " + strconv.Itoa(index) + " using 1:($4==" + strconv.Itoa(rawUsed) + "?$3:1/0) with points ls 1 title '" + rawTitle(index, "used") + "', "
+			ret += "'' index " + strconv.Itoa(index) + " using 1:($4==" + strconv.Itoa(rawWarmup) + "?$3:1/0) with points ls 3 title '" + rawTitle(index, "warm-up") + "', "
+			ret += "'' index " + strconv.Itoa(index) + " using 1:($4==" + strconv.Itoa(rawOutlier) + "?$3:1/0) with points ls 2 title '" + rawTitle(index, "outlier") + "'"
+		}
+		ret += "\n"
+	}
+
+	return a.writeFile("raw.plot", []byte(ret))
+}
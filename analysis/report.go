@@ -0,0 +1,218 @@
+package analysis
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"os"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/jbreitbart/coBench/commands"
+	"github.com/jbreitbart/coBench/plot"
+	"github.com/jbreitbart/coBench/stats"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	reportChartWidth  = 640
+	reportChartHeight = 360
+)
+
+type reportRunT struct {
+	Index   int
+	Mask    string
+	Runtime string
+	State   string
+	Output  string
+}
+
+type reportConfigT struct {
+	ID      string
+	App     string
+	CoSched string
+	CAT     string
+	Mean    string
+	Stddev  string
+	Runs    int
+	Raw     []reportRunT
+}
+
+type reportT struct {
+	Title       string
+	Input       string
+	Generated   string
+	Metric      string
+	Hardware    [][2]string
+	Commandline [][2]string
+	CATCharts   []template.HTML
+	PairCharts  []template.HTML
+	Matrices    []template.HTML
+	Configs     []reportConfigT
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+td, th { border: 1px solid #cccccc; padding: 2px 8px; text-align: left; }
+pre { background: #f4f4f4; padding: 4px; overflow-x: auto; }
+.charts svg { margin: 0 1em 1em 0; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>Input: {{.Input}}<br>Generated: {{.Generated}}<br>Metric: {{.Metric}}</p>
+
+<h2>Hardware</h2>
+<table>{{range .Hardware}}<tr><th>{{index . 0}}</th><td>{{index . 1}}</td></tr>{{end}}</table>
+
+<h2>Configuration</h2>
+<table>{{range .Commandline}}<tr><th>{{index . 0}}</th><td>{{index . 1}}</td></tr>{{end}}</table>
+
+{{if .CATCharts}}<h2>Individual CAT runs</h2>
+<div class="charts">{{range .CATCharts}}{{.}}{{end}}</div>{{end}}
+
+{{if .PairCharts}}<h2>Co-scheduling with CAT</h2>
+<div class="charts">{{range .PairCharts}}{{.}}{{end}}</div>{{end}}
+
+{{if .Matrices}}<h2>Slowdown matrix</h2>
+<p>Slowdown of the row application co-scheduled with the column application. * marks a low confidence.</p>
+<div class="charts">{{range .Matrices}}{{.}}{{end}}</div>{{end}}
+
+<h2>Measurements</h2>
+<table>
+<tr><th>App</th><th>Co-scheduled</th><th>CAT bits</th><th>Mean runtime (s)</th><th>Std.Dev. (s)</th><th>Runs</th><th>Raw logs</th></tr>
+{{range .Configs}}<tr><td>{{.App}}</td><td>{{.CoSched}}</td><td>{{.CAT}}</td><td>{{.Mean}}</td><td>{{.Stddev}}</td><td>{{.Runs}}</td><td><a href="#{{.ID}}">logs</a></td></tr>
+{{end}}</table>
+
+<h2>Raw logs</h2>
+{{range .Configs}}<details id="{{.ID}}">
+<summary>{{.App}}{{if .CoSched}} co-scheduled with {{.CoSched}}{{end}}, CAT bits {{.CAT}}</summary>
+{{range .Raw}}<h4>Run {{.Index}}: {{.Runtime}} s, CAT mask {{.Mask}}{{if .State}} ({{.State}}){{end}}</h4>
+<pre>{{.Output}}</pre>
+{{end}}</details>
+{{end}}
+</body>
+</html>
+`))
+
+// struct fields as name value pairs
+func fieldsToRows(v interface{}) [][2]string {
+	var ret [][2]string
+	val := reflect.ValueOf(v)
+	for i := 0; i < val.NumField(); i++ {
+		ret = append(ret, [2]string{val.Type().Field(i).Name, fmt.Sprintf("%v", val.Field(i).Interface())})
+	}
+	return ret
+}
+
+func (a *AnalyzerT) reportMatrices(apps []string, maxRelCI float64) []plot.HeatmapT {
+	var ret []plot.HeatmapT
+	for _, catBits := range append([]int{0}, a.data.CoSchedCATBits(apps)...) {
+		matrix := a.data.SlowdownMatrix(apps, catBits, a.opts.Metric, stats.DefaultCILevel, maxRelCI)
+
+		hm := plot.HeatmapT{Title: "Slowdown without CAT"}
+		if catBits != 0 {
+			hm.Title = "Slowdown with " + strconv.Itoa(catBits) + " CAT bits for the row app"
+		}
+		valid := false
+		for i, app := range apps {
+			hm.Rows = append(hm.Rows, commands.Pretty(app))
+			hm.Columns = append(hm.Columns, commands.Pretty(app))
+			hm.Values = append(hm.Values, make([]float64, len(apps)))
+			hm.Marked = append(hm.Marked, make([]bool, len(apps)))
+			for j, cell := range matrix[i] {
+				hm.Values[i][j] = math.NaN()
+				if cell.Valid {
+					valid = true
+					hm.Values[i][j] = cell.Slowdown
+					hm.Marked[i][j] = cell.LowConfidence
+				}
+			}
+		}
+		if valid {
+			ret = append(ret, hm)
+		}
+	}
+	return ret
+}
+
+func (a *AnalyzerT) reportConfigs() []reportConfigT {
+	var ret []reportConfigT
+	for i, c := range a.data.Configs() {
+		r := a.data.Runtime(c)
+		_, cat := ConfigToString(c)
+		rc := reportConfigT{
+			ID:     "log-" + strconv.Itoa(i),
+			App:    commands.Pretty(c.App),
+			CAT:    cat,
+			Mean:   fmt.Sprintf("%1.4f", r.Mean),
+			Stddev: fmt.Sprintf("%1.4f", r.Stddev),
+			Runs:   r.Runs,
+		}
+		if c.CoSched != "" {
+			rc.CoSched = commands.Pretty(c.CoSched)
+		}
+		for _, mask := range sortedMasks(r) {
+			for _, run := range (*r.RawRuntimesByMask)[mask] {
+				state := ""
+				if run.Warmup {
+					state = "warm-up"
+				} else if run.Outlier {
+					state = "outlier"
+				}
+				rc.Raw = append(rc.Raw, reportRunT{Index: len(rc.Raw), Mask: fmt.Sprintf("%x", mask), Runtime: fmt.Sprintf("%1.4f", run.Runtime.Seconds()), State: state, Output: run.Output})
+			}
+		}
+		ret = append(ret, rc)
+	}
+
+	return ret
+}
+
+// Writes a single HTML file with all plots as embedded SVG
+func (a *AnalyzerT) createReport(filename string, inputFile string, apps []string, pairs [][2]string, maxRelCI float64) error {
+	log.WithField("file", filename).Infoln("Creating HTML report")
+
+	rep := reportT{
+		Title:       "coBench report",
+		Input:       inputFile,
+		Generated:   time.Now().Format(time.RFC1123),
+		Metric:      a.metricLabel(),
+		Hardware:    fieldsToRows(a.data.Hardware),
+		Commandline: fieldsToRows(a.data.Commandline),
+		Configs:     a.reportConfigs(),
+	}
+
+	for _, app := range apps {
+		if charts := a.indvCATCharts(app, nil); charts != nil {
+			rep.CATCharts = append(rep.CATCharts, template.HTML(charts[0].SVG(reportChartWidth, reportChartHeight)))
+		}
+	}
+	for _, pair := range pairs {
+		if charts := a.coSchedCATCharts(pair, true); charts != nil {
+			rep.PairCharts = append(rep.PairCharts, template.HTML(charts[0].SVG(reportChartWidth, reportChartHeight)))
+		}
+	}
+	for _, hm := range a.reportMatrices(apps, maxRelCI) {
+		size := 120 + 80*len(apps)
+		rep.Matrices = append(rep.Matrices, template.HTML(hm.SVG(size+20, size-30)))
+	}
+
+	file, err := os.Create(a.path(filename))
+	if err != nil {
+		return fmt.Errorf("Cannot create report: %v", err)
+	}
+	defer file.Close()
+
+	if err = reportTemplate.Execute(file, rep); err != nil {
+		return fmt.Errorf("Error while write report %v: %v", filename, err)
+	}
+	return nil
+}
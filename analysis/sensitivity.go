@@ -0,0 +1,131 @@
+package analysis
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/jbreitbart/coBench/commands"
+	"github.com/jbreitbart/coBench/plot"
+	"github.com/jbreitbart/coBench/predict"
+	"github.com/jbreitbart/coBench/sensitivity"
+	"github.com/jbreitbart/coBench/stats"
+	log "github.com/sirupsen/logrus"
+)
+
+// number of points of the fitted curves in the fit dat files
+const fitPoints = 50
+
+// fitted curves of an app as shown in the individual CAT plots
+type fitCurvesT struct {
+	Piecewise []plot.PointT
+	PowerLaw  []plot.PointT
+	// knee on the cache axis
+	Knee float64
+}
+
+// Fits the CAT curve of every app, prints the classification and stores it in cache-sensitivity.csv.
+// Returns the fitted curves by app.
+func (a *AnalyzerT) createCacheSensitivity(apps []string, fitOpts sensitivity.OptionsT, pressureCounter string) (map[string]fitCurvesT, error) {
+	log.Infoln("Fitting the cache sensitivity curves")
+
+	ret := make(map[string]fitCurvesT)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "App\tClass\tScore\tWorking set (MB)\tKnee (bits)\tSlope before knee\tSlope after knee\tPower law exponent")
+	out := "app,class,score,workingset_mb,knee_bits,slope_left,slope_right,powerlaw_a,powerlaw_b,pressure\n"
+
+	for _, app := range apps {
+		if a.data.CATRuntimes(app) == nil {
+			continue
+		}
+
+		profile, err := predict.Profile(a.data, app, pressureCounter, a.opts.Metric)
+		if err != nil {
+			return nil, fmt.Errorf("Cannot profile application %v: %v", app, err)
+		}
+		r, err := sensitivity.Analyze(profile, fitOpts)
+		if err != nil {
+			log.WithError(err).WithField("app", app).Errorln("Skipping cache sensitivity")
+			continue
+		}
+
+		fmt.Fprintf(w, "%v\t%v\t%1.4f\t%1.2f\t%1.1f\t%1.4f\t%1.4f\t%1.4f\n", commands.Pretty(app), r.Class, r.Score,
+			a.catBitsToMB(r.Knee), r.Knee, r.Piecewise.Left.Slope, r.Piecewise.Right.Slope, r.PowerLaw.B)
+
+		out += commands.Pretty(app) + "," + r.Class
+		for _, v := range []float64{r.Score, a.catBitsToMB(r.Knee), r.Knee, r.Piecewise.Left.Slope, r.Piecewise.Right.Slope, r.PowerLaw.A, r.PowerLaw.B, r.Pressure} {
+			out += "," + strconv.FormatFloat(v, 'E', -1, 64)
+		}
+		out += "\n"
+
+		if curves, ok := a.fitCurves(app, r); ok {
+			if err = a.writeFitDatFile(app, curves); err != nil {
+				return nil, err
+			}
+			ret[app] = curves
+		}
+	}
+	w.Flush()
+
+	return ret, a.writeFile("cache-sensitivity.csv", []byte(out))
+}
+
+// converts a fitted slowdown to the values plotted in the individual CAT plots. Returns false if not possible.
+func (a *AnalyzerT) slowdownToPlotValue(app string, s float64) (float64, bool) {
+	if a.opts.Baseline != nil {
+		if a.opts.Baseline.Kind != stats.ReferenceBaseline {
+			return 0, false
+		}
+		return s, true
+	}
+
+	ref := a.data.ReferenceRuntime(app)
+	if a.opts.Median {
+		m, _, _ := ref.MetricQuartiles(a.opts.Metric)
+		return a.scaleSlowdown(m, s), true
+	}
+	mean, _ := ref.MetricStats(a.opts.Metric)
+	return a.scaleSlowdown(mean, s), true
+}
+
+// inverse of stats.Slowdown
+func (a *AnalyzerT) scaleSlowdown(ref float64, s float64) float64 {
+	if rule := a.data.MetricRule(a.opts.Metric); rule != nil && rule.HigherIsBetter {
+		return ref / s
+	}
+	return ref * s
+}
+
+// returns false if the fitted curves cannot be plotted with the current baseline
+func (a *AnalyzerT) fitCurves(app string, r *sensitivity.ResultT) (fitCurvesT, bool) {
+	ret := fitCurvesT{Knee: a.cacheAxis(r.Knee)}
+
+	lo, hi := r.Bits[0], float64(r.CacheBits)
+	for i := 0; i < fitPoints; i++ {
+		x := lo + (hi-lo)*float64(i)/float64(fitPoints-1)
+		pw, ok := a.slowdownToPlotValue(app, r.Piecewise.At(x))
+		if !ok {
+			return ret, false
+		}
+		pl, _ := a.slowdownToPlotValue(app, r.PowerLaw.At(x))
+		ret.Piecewise = append(ret.Piecewise, plot.Point(a.cacheAxis(x), pw))
+		ret.PowerLaw = append(ret.PowerLaw, plot.Point(a.cacheAxis(x), pl))
+	}
+	return ret, true
+}
+
+func (a *AnalyzerT) writeFitDatFile(app string, curves fitCurvesT) error {
+	out := "# " + app + "\n"
+	out += "# L3 piecewise-linear power-law\n"
+	for i, p := range curves.Piecewise {
+		out += strconv.FormatFloat(p.X, 'E', -1, 64) + " " + strconv.FormatFloat(p.Y, 'E', -1, 64) + " " + strconv.FormatFloat(curves.PowerLaw[i].Y, 'E', -1, 64) + "\n"
+	}
+
+	return a.writeFile(indvCATFitFilename(app), []byte(out))
+}
+
+func indvCATFitFilename(app string) string {
+	return commands.Pretty(app) + "-cat-fit.dat"
+}
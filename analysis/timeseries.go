@@ -0,0 +1,212 @@
+package analysis
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/jbreitbart/coBench/commands"
+	"github.com/jbreitbart/coBench/plot"
+	"github.com/jbreitbart/coBench/stats"
+	log "github.com/sirupsen/logrus"
+)
+
+type perfSeriesFileT struct {
+	Filename string
+	Apps     []string
+	Names    []string
+}
+
+func sortedMasks(dat *stats.RuntimeT) []uint64 {
+	var masks []uint64
+	for k := range *dat.RawRuntimesByMask {
+		masks = append(masks, k)
+	}
+	sort.Slice(masks, func(i, j int) bool {
+		return masks[i] < masks[j]
+	})
+	return masks
+}
+
+func sortedRuns(dat *stats.RuntimeT) []stats.DataPerRun {
+	var ret []stats.DataPerRun
+	for _, m := range sortedMasks(dat) {
+		ret = append(ret, (*dat.RawRuntimesByMask)[m]...)
+	}
+	return ret
+}
+
+// one line per sampling interval, offset is added to the time of every interval
+func perfSeriesRunToString(run stats.DataPerRun, names []string, offset float64) string {
+	seen := make(map[float64]bool)
+	var times []float64
+	values := make(map[string]map[float64]float64)
+	for _, name := range names {
+		values[name] = make(map[float64]float64)
+		for _, s := range run.PerfSeries[name] {
+			values[name][s.Time] = s.Value
+			if !seen[s.Time] {
+				seen[s.Time] = true
+				times = append(times, s.Time)
+			}
+		}
+	}
+	sort.Float64s(times)
+
+	var out string
+	for _, t := range times {
+		out += strconv.FormatFloat(t+offset, 'E', -1, 64)
+		for _, name := range names {
+			v, exists := values[name][t]
+			if !exists {
+				out += " NaN"
+				continue
+			}
+			out += " " + strconv.FormatFloat(v, 'E', -1, 64)
+		}
+		out += "\n"
+	}
+	return out
+}
+
+func perfSeriesHeader(names []string) string {
+	out := "# Time(s)"
+	for _, name := range names {
+		out += " " + name
+	}
+	return out + "\n"
+}
+
+// Returns the perf time series of every app without co-scheduling; every run is one line in the plot
+func (a *AnalyzerT) createIndvPerfSeriesDatFiles(apps []string) ([]perfSeriesFileT, error) {
+	log.Infoln("Creating dat files for individual perf time series")
+
+	ret := make([]perfSeriesFileT, 0)
+
+	for _, app := range apps {
+		ref := a.data.ReferenceRuntime(app)
+		names := stats.PerfSeriesNames(ref)
+		if len(names) == 0 {
+			continue
+		}
+
+		out := "# " + app + "\n"
+		out += perfSeriesHeader(names)
+		for _, run := range sortedRuns(ref) {
+			out += perfSeriesRunToString(run, names, 0) + "\n"
+		}
+
+		filename := commands.Pretty(app) + "-perf-series.dat"
+		if err := a.writeFile(filename, []byte(out)); err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, perfSeriesFileT{Filename: filename, Apps: []string{app}, Names: names})
+	}
+
+	return ret, nil
+}
+
+// Returns the perf time series of co-scheduled apps on a common time axis; gnuplot index i is app i
+func (a *AnalyzerT) createCoSchedPerfSeriesDatFiles(pairs [][2]string) ([]perfSeriesFileT, error) {
+	log.Infoln("Creating dat files for co-scheduling perf time series")
+
+	ret := make([]perfSeriesFileT, 0)
+
+	for _, pair := range pairs {
+		rs := [2]*stats.RuntimeT{a.data.CoSchedRuntimes(pair[0], pair[1]), a.data.CoSchedRuntimes(pair[1], pair[0])}
+		if rs[0] == nil || rs[1] == nil {
+			continue
+		}
+
+		names := stats.PerfSeriesNames(rs[0])
+		if len(names) == 0 || !equalStrings(names, stats.PerfSeriesNames(rs[1])) {
+			continue
+		}
+
+		// the earliest start of both apps is time 0
+		var runs [2][]stats.DataPerRun
+		var t0 int64
+		for i, r := range rs {
+			runs[i] = sortedRuns(r)
+			for _, run := range runs[i] {
+				if t0 == 0 || run.Start.UnixNano() < t0 {
+					t0 = run.Start.UnixNano()
+				}
+			}
+		}
+
+		out := "# 0: " + pair[0] + "\n"
+		out += "# co-scheduled with \n"
+		out += "# 1: " + pair[1] + "\n"
+		out += perfSeriesHeader(names)
+		for i := range runs {
+			out += "# " + strconv.Itoa(i) + "\n"
+			for _, run := range runs[i] {
+				offset := float64(run.Start.UnixNano()-t0) / 1e9
+				out += perfSeriesRunToString(run, names, offset) + "\n"
+			}
+			out += "\n"
+		}
+
+		filename := commands.Pretty(pair[0]) + "-" + commands.Pretty(pair[1]) + "-cosched-perf-series.dat"
+		if err := a.writeFile(filename, []byte(out)); err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, perfSeriesFileT{Filename: filename, Apps: pair[:], Names: names})
+	}
+
+	return ret, nil
+}
+
+func (a *AnalyzerT) writeGNUPlotPerfSeriesFile(files []perfSeriesFileT) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	log.Infoln("Creating plot file for perf time series")
+
+	var ret string
+	ret += "set output 'perf-series.pdf'\n"
+	ret += gnuplotHeader()
+	ret += "set xlabel 'Time (s)'\n"
+
+	for _, f := range files {
+		title := plot.GnuplotEscape(commands.Pretty(f.Apps[0]))
+		if len(f.Apps) > 1 {
+			title += " + " + plot.GnuplotEscape(commands.Pretty(f.Apps[1]))
+		}
+		ret += "set title '" + title + "'\n"
+
+		for k, name := range f.Names {
+			ret += "set ylabel '" + plot.GnuplotEscape(name) + "'\n"
+			ret += "plot "
+			for i, app := range f.Apps {
+				if i > 0 {
+					ret += ", "
+				}
+				ret += "'" + f.Filename + "' "
+				if len(f.Apps) > 1 {
+					ret += "index " + strconv.Itoa(i) + " "
+				}
+				ret += "using 1:" + strconv.Itoa(k+2) + " with lines ls " + strconv.Itoa(i+1)
+				ret += " title '" + plot.GnuplotEscape(name) + " (" + plot.GnuplotEscape(commands.Pretty(app)) + ")'"
+			}
+			ret += "\n"
+		}
+	}
+
+	return a.writeFile("perf-series.plot", []byte(ret))
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,294 @@
+// Package cgroup controls the cgroup v2 groups of coBench. Below a root there is one group per slot, i.e. per CPU list
+// of the command line, holding the CPUs and the limits of the slot. Every run is executed in a new child group of its slot, so the
+// statistics of the child group describe exactly this run.
+package cgroup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/jbreitbart/coBench/stats"
+)
+
+// EnvVar is set to the group of the run in the environment of the commands returned by Command
+const EnvVar = "COBENCH_CGROUP"
+
+// controllers enabled for the slot groups and their run groups. cpuset keeps every process moved into a run group,
+// e.g. the init process of a container, on the CPUs of its slot.
+var controllers = []string{"cpuset", "cpu", "memory", "io"}
+
+// limits that can be set in a slot group
+var limits = map[string]bool{
+	"cpu.max":     true,
+	"memory.max":  true,
+	"memory.high": true,
+	"io.max":      true,
+}
+
+// resources reporting their pressure stall information in <resource>.pressure
+var pressureResources = []string{"cpu", "memory", "io"}
+
+// Dirs returns the groups of the 1st and 2nd slot
+func Dirs(root string) []string {
+	return []string{filepath.Join(root, "cobench0"), filepath.Join(root, "cobench1")}
+}
+
+// Validate checks that the settings have unique names and only use supported limits
+func Validate(settings []stats.CgroupSettingT) error {
+	names := make(map[string]bool)
+	for _, s := range settings {
+		if s.Name == "" {
+			return fmt.Errorf("cgroup setting without a name")
+		}
+		if names[s.Name] {
+			return fmt.Errorf("cgroup setting %v is defined twice", s.Name)
+		}
+		names[s.Name] = true
+
+		for _, l := range s.Limits {
+			for file := range l {
+				if !limits[file] {
+					return fmt.Errorf("cgroup setting %v uses the unsupported limit %v", s.Name, file)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ReadSweep reads the settings of a sweep from a JSON file, e.g.
+// [{"Name": "mem-1G", "Limits": [{"memory.max": "1G"}, {"memory.max": "1G"}]}]
+func ReadSweep(filename string) ([]stats.CgroupSettingT, error) {
+	raw, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var ret []stats.CgroupSettingT
+	if err = json.Unmarshal(raw, &ret); err != nil {
+		return nil, err
+	}
+	return ret, Validate(ret)
+}
+
+// Setup creates the slot groups below root with the CPUs and the limits of the 1st and 2nd slot. Existing slot groups
+// are removed first, so no limit of a previous setting is left.
+func Setup(root string, cpus [2]string, slotLimits [2]stats.CgroupLimitsT) error {
+	if err := Reset(root); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return fmt.Errorf("cgroup: %v", err)
+	}
+	if err := enableControllers(root); err != nil {
+		return err
+	}
+
+	for i, dir := range Dirs(root) {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			return fmt.Errorf("cgroup: %v", err)
+		}
+		// the processes are in the run groups, so the controllers are enabled for them as well
+		if err := enableControllers(dir); err != nil {
+			return err
+		}
+		if err := write(filepath.Join(dir, "cpuset.cpus"), cpus[i]); err != nil {
+			return fmt.Errorf("cgroup could not set the CPUs %v: %v", cpus[i], err)
+		}
+
+		files := make([]string, 0, len(slotLimits[i]))
+		for file := range slotLimits[i] {
+			files = append(files, file)
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			if err := write(filepath.Join(dir, file), slotLimits[i][file]); err != nil {
+				return fmt.Errorf("cgroup could not set %v: %v", file, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Reset removes the slot groups of coBench below root, including the run groups left behind by an aborted benchmark.
+// Missing groups are ignored.
+func Reset(root string) error {
+	for _, dir := range Dirs(root) {
+		runs, _ := filepath.Glob(filepath.Join(dir, "run*"))
+		for _, run := range runs {
+			if err := RemoveRun(run); err != nil {
+				return err
+			}
+		}
+		if err := remove(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewRun creates a new run group in the slot group and returns its path
+func NewRun(slot string) (string, error) {
+	ret, err := ioutil.TempDir(slot, "run")
+	if err != nil {
+		return "", fmt.Errorf("cgroup: %v", err)
+	}
+	return ret, nil
+}
+
+// RemoveRun removes a run group after all of its processes finished
+func RemoveRun(dir string) error {
+	return remove(dir)
+}
+
+// AddProcess moves the process into the group. Processes it starts afterwards are in the group as well.
+func AddProcess(dir string, pid int) error {
+	if err := write(filepath.Join(dir, "cgroup.procs"), strconv.Itoa(pid)); err != nil {
+		return fmt.Errorf("cgroup could not add process %v: %v", pid, err)
+	}
+	return nil
+}
+
+// Command returns the command line executing args in the group named by EnvVar. The shell moves itself into the group
+// and is replaced by args, so every process of the run is in the group from its start.
+func Command(args []string) []string {
+	return append([]string{"/bin/sh", "-c", `echo $$ > "$` + EnvVar + `/cgroup.procs" && exec "$@"`, "cobench-cgroup"}, args...)
+}
+
+// ReadStats returns the statistics of a group: memory.peak, cpu.stat and the pressure stall information. Files the
+// kernel does not provide, e.g. without the memory controller, are skipped.
+func ReadStats(dir string) (*stats.CgroupStatsT, error) {
+	var ret stats.CgroupStatsT
+
+	if peak, err := readOptional(filepath.Join(dir, "memory.peak")); err != nil {
+		return nil, err
+	} else if peak != "" {
+		if ret.MemoryPeak, err = strconv.ParseUint(strings.TrimSpace(peak), 10, 64); err != nil {
+			return nil, fmt.Errorf("cgroup: invalid memory.peak %q", peak)
+		}
+	}
+
+	cpuStat, err := readOptional(filepath.Join(dir, "cpu.stat"))
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(cpuStat, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cgroup: invalid cpu.stat line %q", line)
+		}
+		if ret.CPUStat == nil {
+			ret.CPUStat = make(map[string]uint64)
+		}
+		ret.CPUStat[fields[0]] = v
+	}
+
+	for _, resource := range pressureResources {
+		pressure, err := readOptional(filepath.Join(dir, resource+".pressure"))
+		if err != nil {
+			return nil, err
+		}
+		lines, err := parsePressure(pressure)
+		if err != nil {
+			return nil, fmt.Errorf("cgroup: invalid %v.pressure: %v", resource, err)
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		if ret.Pressure == nil {
+			ret.Pressure = make(map[string]map[string]stats.PressureT)
+		}
+		ret.Pressure[resource] = lines
+	}
+
+	return &ret, nil
+}
+
+// parses the lines of a PSI file, e.g. "some avg10=0.00 avg60=0.00 avg300=0.00 total=0"
+func parsePressure(content string) (map[string]stats.PressureT, error) {
+	var ret map[string]stats.PressureT
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		var p stats.PressureT
+		for _, field := range fields[1:] {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("unexpected field %q", field)
+			}
+			var err error
+			switch kv[0] {
+			case "avg10":
+				p.Avg10, err = strconv.ParseFloat(kv[1], 64)
+			case "avg60":
+				p.Avg60, err = strconv.ParseFloat(kv[1], 64)
+			case "avg300":
+				p.Avg300, err = strconv.ParseFloat(kv[1], 64)
+			case "total":
+				p.Total, err = strconv.ParseUint(kv[1], 10, 64)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("unexpected field %q", field)
+			}
+		}
+
+		if ret == nil {
+			ret = make(map[string]stats.PressureT)
+		}
+		ret[fields[0]] = p
+	}
+	return ret, nil
+}
+
+// enables the controllers for the children of the group
+func enableControllers(dir string) error {
+	value := "+" + strings.Join(controllers, " +")
+	if err := write(filepath.Join(dir, "cgroup.subtree_control"), value); err != nil {
+		return fmt.Errorf("cgroup could not enable the controllers %v in %v: %v", strings.Join(controllers, ", "), dir, err)
+	}
+	return nil
+}
+
+// writes an interface file. Missing files are created for a fake cgroup file system in a normal directory.
+func write(filename string, value string) error {
+	return ioutil.WriteFile(filename, []byte(value), 0644)
+}
+
+// content of a file, empty if it does not exist
+func readOptional(filename string) (string, error) {
+	content, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("cgroup: %v", err)
+	}
+	return string(content), nil
+}
+
+// removes a group. The interface files of a cgroup file system vanish with their group, a fake cgroup file system is a
+// normal directory whose files are removed first.
+func remove(dir string) error {
+	err := os.Remove(dir)
+	if errors.Is(err, syscall.ENOTEMPTY) {
+		err = os.RemoveAll(dir)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Cannot remove cgroup %v: %v", dir, err)
+	}
+	return nil
+}
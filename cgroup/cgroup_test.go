@@ -0,0 +1,150 @@
+package cgroup
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jbreitbart/coBench/stats"
+)
+
+func readFile(t *testing.T, filename string) string {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func TestSetup(t *testing.T) {
+	root := t.TempDir()
+
+	limits := [2]stats.CgroupLimitsT{{"memory.max": "1G", "cpu.max": "50000 100000"}, {"io.max": "8:0 rbps=1048576"}}
+	cpus := [2]string{"0-3", "4-7"}
+	if err := Setup(root, cpus, limits); err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range append([]string{root}, Dirs(root)...) {
+		if c := readFile(t, dir+"/cgroup.subtree_control"); c != "+cpuset +cpu +memory +io" {
+			t.Errorf("%v enables the controllers %q", dir, c)
+		}
+	}
+	for i, dir := range Dirs(root) {
+		if c := readFile(t, dir+"/cpuset.cpus"); c != cpus[i] {
+			t.Errorf("%v uses the CPUs %q, expected %q", dir, c, cpus[i])
+		}
+		for file, value := range limits[i] {
+			if c := readFile(t, filepath.Join(dir, file)); c != value {
+				t.Errorf("%v/%v = %q, expected %q", dir, file, c, value)
+			}
+		}
+	}
+
+	// a run group left behind and the limits of the previous setting are removed
+	if _, err := NewRun(Dirs(root)[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := Setup(root, cpus, [2]stats.CgroupLimitsT{{"memory.high": "512M"}, nil}); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := filepath.Glob(Dirs(root)[0] + "/*")
+	expected := []string{Dirs(root)[0] + "/cgroup.subtree_control", Dirs(root)[0] + "/cpuset.cpus", Dirs(root)[0] + "/memory.high"}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("Slot group contains %v, expected %v", entries, expected)
+	}
+
+	if err := Reset(root); err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range Dirs(root) {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("%v was not removed: %v", dir, err)
+		}
+	}
+	// a second reset ignores the missing groups
+	if err := Reset(root); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestReadStats(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"memory.peak":     "1048576\n",
+		"cpu.stat":        "usage_usec 2000\nuser_usec 1500\nsystem_usec 500\nnr_throttled 2\nthrottled_usec 300\n",
+		"memory.pressure": "some avg10=1.50 avg60=0.20 avg300=0.00 total=1234\nfull avg10=0.50 avg60=0.10 avg300=0.00 total=567\n",
+		"cpu.pressure":    "some avg10=0.00 avg60=0.00 avg300=0.00 total=42\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s, err := ReadStats(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &stats.CgroupStatsT{
+		MemoryPeak: 1048576,
+		CPUStat:    map[string]uint64{"usage_usec": 2000, "user_usec": 1500, "system_usec": 500, "nr_throttled": 2, "throttled_usec": 300},
+		Pressure: map[string]map[string]stats.PressureT{
+			"memory": {"some": {Avg10: 1.5, Avg60: 0.2, Total: 1234}, "full": {Avg10: 0.5, Avg60: 0.1, Total: 567}},
+			"cpu":    {"some": {Total: 42}},
+		},
+	}
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf("ReadStats = %+v, expected %+v", s, expected)
+	}
+
+	// a group without statistics, e.g. without controllers
+	if s, err = ReadStats(t.TempDir()); err != nil || !reflect.DeepEqual(s, &stats.CgroupStatsT{}) {
+		t.Errorf("ReadStats of an empty group = %+v, %v", s, err)
+	}
+
+	if err = ioutil.WriteFile(filepath.Join(dir, "io.pressure"), []byte("some avg10=x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ReadStats(dir); err == nil {
+		t.Errorf("Invalid io.pressure accepted")
+	}
+}
+
+// the command is executed by the process moved into the group
+func TestCommand(t *testing.T) {
+	group, err := NewRun(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := Command([]string{"/bin/sh", "-c", "echo $$"})
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Env = append(os.Environ(), EnvVar+"="+group)
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if procs := readFile(t, group+"/cgroup.procs"); strings.TrimSpace(procs) != strings.TrimSpace(string(out)) {
+		t.Errorf("cgroup.procs contains %q, the command has the pid %q", procs, out)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := []stats.CgroupSettingT{{Name: "a", Limits: [2]stats.CgroupLimitsT{{"cpu.max": "max"}}}, {Name: "b"}}
+	if err := Validate(valid); err != nil {
+		t.Errorf("Valid settings rejected: %v", err)
+	}
+
+	for _, settings := range [][]stats.CgroupSettingT{
+		{{Name: ""}},
+		{{Name: "a"}, {Name: "a"}},
+		{{Name: "a", Limits: [2]stats.CgroupLimitsT{nil, {"cpuset.cpus": "0"}}}},
+	} {
+		if err := Validate(settings); err == nil {
+			t.Errorf("Invalid settings %+v accepted", settings)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jbreitbart/coBench/analysis"
+	"github.com/jbreitbart/coBench/plot"
+	"github.com/jbreitbart/coBench/stats"
+	log "github.com/sirupsen/logrus"
+)
+
+// flags shared by analyze and report
+type analysisFlagsT struct {
+	metricRules *string
+	normalize   *string
+	plots       *string
+}
+
+func addAnalysisFlags(flags *flag.FlagSet, opts *analysis.OptionsT) analysisFlagsT {
+	var ret analysisFlagsT
+	ret.metricRules = flags.String("metrics", "", "JSON file containing metric rules, replaces the rules stored in the input file")
+	flags.StringVar(&opts.Metric, "metric", opts.Metric, "Metric used for the plots and slowdowns")
+	flags.BoolVar(&opts.Median, "median", false, "Plot the median with the interquartile range instead of mean and standard deviation")
+	flags.Float64Var(&opts.MatrixCI, "matrix-ci", opts.MatrixCI, "Mark cells of the slowdown matrix with a confidence interval wider than ±matrix-ci as low confidence")
+	ret.normalize = flags.String("normalize", "", "Plot the slowdown compared to a baseline: reference, best-cat or cat:<bits>")
+	ret.plots = flags.String("plot", "gnuplot", "Comma separated backends used for the CAT plots: "+strings.Join(plot.Backends(), ", "))
+	return ret
+}
+
+// reads the input file and returns the analyzer of opts completed with the shared flags
+func newAnalyzer(inputFile string, f analysisFlagsT, opts *analysis.OptionsT) *analysis.AnalyzerT {
+	if *f.normalize != "" {
+		b, err := stats.ParseBaseline(*f.normalize)
+		if err != nil {
+			log.WithError(err).Fatalln("Invalid baseline")
+		}
+		opts.Baseline = &b
+	}
+
+	backends, err := analysis.ParsePlotBackends(*f.plots)
+	if err != nil {
+		log.WithError(err).Fatalln("Invalid plot backend")
+	}
+	opts.PlotBackends = backends
+
+	data, err := analysis.Load(inputFile, *f.metricRules)
+	if err != nil {
+		log.WithError(err).WithField("file", inputFile).Fatalln("Cannot read input file")
+	}
+
+	a, err := analysis.New(data, *opts)
+	if err != nil {
+		log.WithError(err).Fatalln("Invalid analysis options")
+	}
+	return a
+}
+
+// cobench analyze [options] -input result.json
+func analyzeMain(args []string) {
+	opts := analysis.DefaultOptions()
+
+	flags := flag.NewFlagSet("analyze", flag.ExitOnError)
+	inputFile := flags.String("input", "", "Input result file")
+	shared := addAnalysisFlags(flags, &opts)
+	flags.BoolVar(&opts.MatrixCAT, "matrix-cat", false, "Create a slowdown matrix for every CAT configuration")
+	flags.StringVar(&opts.Partition, "partition", "", "Find the best cache partition of every pair. Objective: sum, max, ws or qos")
+	flags.StringVar(&opts.QoSApp, "qos-app", "", "Application whose slowdown must not exceed -qos-target (partition objective qos)")
+	flags.Float64Var(&opts.QoSTarget, "qos-target", opts.QoSTarget, "Maximum slowdown of -qos-app (partition objective qos)")
+	flags.StringVar(&opts.Advise, "advise", "", "Recommend a co-schedule of the job mix. Objective: stp or max")
+	flags.StringVar(&opts.Jobs, "jobs", "", "Comma separated job mix used by -advise. Default: every application once")
+	flags.IntVar(&opts.Slots, "slots", opts.Slots, "Jobs per node used by -advise")
+	flags.BoolVar(&opts.AdviseCAT, "advise-cat", false, "Also choose the CAT split of every pair (-advise with 2 slots only)")
+	flags.StringVar(&opts.Predict, "predict", "", "Predict the slowdown of every pair from the individual CAT runs with a model: half or proportional")
+	flags.StringVar(&opts.Pressure, "pressure", "", "perf counter used as cache or bandwidth pressure by -predict and -fit, e.g. LLC-load-misses")
+	flags.BoolVar(&opts.Fit, "fit", false, "Fit the cache sensitivity curve of every application and classify it")
+	flags.Float64Var(&opts.Sensitivity.Threshold, "fit-threshold", opts.Sensitivity.Threshold, "Applications with a smaller slowdown - 1 at the smallest CAT allocation are cache-insensitive (-fit)")
+	flags.Float64Var(&opts.Sensitivity.StreamingPressure, "streaming-pressure", 0, "Cache-insensitive applications with at least this rate of the -pressure counter per second are streaming (-fit)")
+	flags.StringVar(&opts.OutputDir, "output-dir", "", "Directory of the written files. Default: the current directory")
+	flags.StringVar(&opts.Report, "report", "", "Write a self-contained HTML report to this file")
+	flags.StringVar(&opts.ExportRuns, "export", "", "Write every run as one row to this file, comma separated or tab separated for .tsv")
+	flags.StringVar(&opts.ExportConfigs, "export-configs", "", "Write one row with the statistics of every configuration to this file, comma separated or tab separated for .tsv")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: cobench analyze [options] -input result.json")
+		fmt.Fprintln(flags.Output(), "Writes dat files, plots, slowdown matrices and recommendations of a result file to the output directory.")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if *inputFile == "" || flags.NArg() != 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	if err := newAnalyzer(*inputFile, shared, &opts).Run(*inputFile); err != nil {
+		log.WithError(err).Fatalln("Analysis failed")
+	}
+}
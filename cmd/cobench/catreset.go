@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jbreitbart/coBench/runner"
+	log "github.com/sirupsen/logrus"
+)
+
+// cobench cat-reset [options]
+func catResetMain(args []string) {
+	flags := flag.NewFlagSet("cat-reset", flag.ExitOnError)
+	resctrlPath := flags.String("resctrl", runner.DefaultOptions().ResctrlPath, "Root path of the resctrl file system")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: cobench cat-reset [options]")
+		fmt.Fprintln(flags.Output(), "Removes the resctrl groups left behind by an aborted cobench run.")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	if err := runner.ResetCAT(*resctrlPath); err != nil {
+		log.WithError(err).Fatalln("Cannot reset CAT")
+	}
+	log.WithField("resctrl", *resctrlPath).Infoln("CAT reset")
+}
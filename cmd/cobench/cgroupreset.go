@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jbreitbart/coBench/cgroup"
+	log "github.com/sirupsen/logrus"
+)
+
+// cobench cgroup-reset -cgroup-root <path>
+func cgroupResetMain(args []string) {
+	flags := flag.NewFlagSet("cgroup-reset", flag.ExitOnError)
+	root := flags.String("cgroup-root", "", "cgroup v2 group passed to cobench run")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: cobench cgroup-reset -cgroup-root <path>")
+		fmt.Fprintln(flags.Output(), "Removes the cgroups left behind by an aborted cobench run.")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 0 || *root == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	if err := cgroup.Reset(*root); err != nil {
+		log.WithError(err).Fatalln("Cannot reset the cgroups")
+	}
+	log.WithField("cgroup-root", *root).Infoln("cgroups reset")
+}
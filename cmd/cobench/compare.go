@@ -0,0 +1,101 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/jbreitbart/coBench/analysis"
+	"github.com/jbreitbart/coBench/commands"
+	"github.com/jbreitbart/coBench/stats"
+	log "github.com/sirupsen/logrus"
+)
+
+// exit code of cobench compare if a regression was found
+const regressionExitCode = 3
+
+// cobench compare [options] old.json new.json
+func compareMain(args []string) {
+	flags := flag.NewFlagSet("compare", flag.ExitOnError)
+	test := flags.String("test", stats.WelchTest, "Statistical test: welch (Welch's t-test) or mwu (Mann-Whitney U test)")
+	alpha := flags.Float64("alpha", 0.05, "Significance level")
+	threshold := flags.Float64("threshold", 0.05, "Relative runtime increase counted as a regression. cobench compare exits with code 3 on a significant regression")
+	all := flags.Bool("all", false, "Also print configurations without a significant change")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: cobench compare [options] old.json new.json")
+		fmt.Fprintln(flags.Output(), "Compares every configuration measured in both result files and reports significant runtime changes.")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	old, err := stats.ReadDataset(flags.Arg(0))
+	if err != nil {
+		log.WithError(err).WithField("file", flags.Arg(0)).Fatalln("Cannot read input file")
+	}
+	new, err := stats.ReadDataset(flags.Arg(1))
+	if err != nil {
+		log.WithError(err).WithField("file", flags.Arg(1)).Fatalln("Cannot read input file")
+	}
+
+	for _, c := range stats.UnmatchedConfigs(old, new) {
+		cosched, cat := analysis.ConfigToString(c.Config)
+		log.WithFields(log.Fields{"co-scheduled": cosched, "CAT bits": cat, "CAT mask": maskToString(c.CATMask)}).Warnf("%v only measured in %v", commands.Pretty(c.Config.App), flags.Arg(0))
+	}
+	for _, c := range stats.UnmatchedConfigs(new, old) {
+		cosched, cat := analysis.ConfigToString(c.Config)
+		log.WithFields(log.Fields{"co-scheduled": cosched, "CAT bits": cat, "CAT mask": maskToString(c.CATMask)}).Warnf("%v only measured in %v", commands.Pretty(c.Config.App), flags.Arg(1))
+	}
+
+	cmps, err := stats.Compare(old, new, stats.CompareOptionsT{Test: *test, Alpha: *alpha, Threshold: *threshold})
+	if err != nil {
+		log.WithError(err).Fatalln("Comparison failed")
+	}
+
+	effect := "Hedges' g"
+	if *test == stats.MannWhitneyTest {
+		effect = "Cliff's delta"
+	}
+
+	regressions := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "App\tCo-scheduled\tCAT bits\tCAT mask\tOld (s)\tNew (s)\tChange\tp\t%v\tRuns\tRegression\n", effect)
+	for _, c := range cmps {
+		if c.Regression {
+			regressions++
+		}
+		if !c.Significant && !*all {
+			continue
+		}
+		cosched, cat := analysis.ConfigToString(c.Config)
+		regression := ""
+		if c.Regression {
+			regression = "*"
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%1.4f\t%1.4f\t%+2.2f%%\t%1.4f\t%+1.3f\t%v/%v\t%v\n", commands.Pretty(c.Config.App), cosched, cat, maskToString(c.CATMask),
+			c.OldMean, c.NewMean, 100*c.Change, c.P, c.EffectSize, c.OldRuns, c.NewRuns, regression)
+	}
+	w.Flush()
+
+	log.WithFields(log.Fields{
+		"configurations": len(cmps),
+		"regressions":    regressions,
+	}).Infoln("Comparison done")
+
+	if regressions > 0 {
+		os.Exit(regressionExitCode)
+	}
+}
+
+// hex CAT mask, "-" without CAT
+func maskToString(mask uint64) string {
+	if mask == stats.NoCATMask {
+		return "-"
+	}
+	return fmt.Sprintf("%#x", mask)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jbreitbart/coBench/stats"
+	log "github.com/sirupsen/logrus"
+)
+
+// cobench db list|import|export ...
+func dbMain(args []string) {
+	flags := flag.NewFlagSet("db", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: cobench db list results.db")
+		fmt.Fprintln(flags.Output(), "       cobench db import results.db a.json b.json ...")
+		fmt.Fprintln(flags.Output(), "       cobench db export results.db[#campaign] out.json")
+		fmt.Fprintln(flags.Output(), "Lists, imports and exports the campaigns of a result database.")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() < 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	location := flags.Arg(1)
+	st := stats.GetStorage(location)
+	if st == nil {
+		log.WithField("file", location).Fatalln("No storage for this file type. The SQLite storage requires building with -tags sqlite")
+	}
+	path, id, err := stats.SplitLocation(location)
+	if err != nil {
+		log.WithError(err).Fatalln("Invalid database")
+	}
+
+	switch {
+	case flags.Arg(0) == "list" && flags.NArg() == 2:
+		campaigns, err := st.Campaigns(path)
+		if err != nil {
+			log.WithError(err).WithField("file", path).Fatalln("Cannot list campaigns")
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tName\tCreated\tConfigurations\tRuns")
+		for _, c := range campaigns {
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\n", c.ID, c.Name, c.Created.Format(time.RFC3339), c.Configs, c.Runs)
+		}
+		w.Flush()
+
+	case flags.Arg(0) == "import" && flags.NArg() > 2:
+		for _, filename := range flags.Args()[2:] {
+			d, err := stats.ReadDataset(filename)
+			if err != nil {
+				log.WithError(err).WithField("file", filename).Fatalln("Cannot read input file")
+			}
+			id, err := st.Store(path, filepath.Base(filename), d)
+			if err != nil {
+				log.WithError(err).WithField("file", filename).Fatalln("Cannot import result file")
+			}
+			log.WithFields(log.Fields{
+				"file":     filename,
+				"campaign": id,
+			}).Infoln("Result file imported")
+		}
+
+	case flags.Arg(0) == "export" && flags.NArg() == 3:
+		d, err := st.Read(path, id)
+		if err != nil {
+			log.WithError(err).WithField("file", location).Fatalln("Cannot read campaign")
+		}
+		raw, err := json.Marshal(d)
+		if err != nil {
+			log.WithError(err).Fatalln("Cannot create JSON")
+		}
+		if err = ioutil.WriteFile(flags.Arg(2), raw, 0644); err != nil {
+			log.WithError(err).WithField("file", flags.Arg(2)).Fatalln("Error while write file")
+		}
+
+	default:
+		flags.Usage()
+		os.Exit(2)
+	}
+}
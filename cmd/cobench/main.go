@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+type subcommandT struct {
+	name string
+	help string
+	main func(args []string)
+}
+
+var subcommands = []subcommandT{
+	{"run", "Measure the applications individually and co-scheduled", runMain},
+	{"analyze", "Write dat files, plots and recommendations of a result file", analyzeMain},
+	{"compare", "Report significant runtime changes between two result files", compareMain},
+	{"merge", "Merge result files of the same benchmark", mergeMain},
+	{"report", "Write a self-contained HTML report of a result file", reportMain},
+	{"query", "Filter and aggregate result files", queryMain},
+	{"db", "List, import and export the campaigns of a result database", dbMain},
+	{"cat-reset", "Remove the resctrl groups left behind by an aborted run", catResetMain},
+	{"cgroup-reset", "Remove the cgroups left behind by an aborted run", cgroupResetMain},
+	{"sysinfo", "Print the hardware information stored in the result files", sysinfoMain},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: cobench <command> [options]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	for _, c := range subcommands {
+		fmt.Fprintf(os.Stderr, "  %-12v %v\n", c.name, c.help)
+	}
+	fmt.Fprintln(os.Stderr, "\nRun cobench <command> -h for the options of a command.")
+}
+
+func main() {
+	//log.SetLevel(log.DebugLevel)
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "help", "-h", "-help", "--help":
+		usage()
+		return
+	}
+
+	for _, c := range subcommands {
+		if c.name == os.Args[1] {
+			c.main(os.Args[2:])
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Unknown command %v\n\n", os.Args[1])
+	usage()
+	os.Exit(2)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jbreitbart/coBench/stats"
+	log "github.com/sirupsen/logrus"
+)
+
+// cobench merge [options] a.json b.json ...
+func mergeMain(args []string) {
+	flags := flag.NewFlagSet("merge", flag.ExitOnError)
+	output := flags.String("output", "merged.result.json", "Output result file")
+	force := flags.Bool("force", false, "Merge result files measured on different hardware or with different run semantics")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: cobench merge [options] a.json b.json ...")
+		fmt.Fprintln(flags.Output(), "Merges the runs of multiple result files of the same benchmark into one result file.")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() < 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	var datasets []*stats.StatsT
+	for _, filename := range flags.Args() {
+		d, err := stats.ReadDataset(filename)
+		if err != nil {
+			log.WithError(err).WithField("file", filename).Fatalln("Cannot read input file")
+		}
+		datasets = append(datasets, d)
+	}
+
+	merged, warnings, err := stats.Merge(datasets, *force)
+	for _, w := range warnings {
+		log.Warnln(w)
+	}
+	if err != nil {
+		log.WithError(err).Fatalln("Cannot merge result files. Use -force to merge anyway")
+	}
+
+	err = merged.StoreToFile(*output)
+	if err != nil {
+		log.WithError(err).WithField("file", *output).Fatalln("Error store merged result file")
+	}
+
+	log.WithFields(log.Fields{
+		"files": flags.NArg(),
+		"file":  *output,
+	}).Infoln("Result files merged")
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jbreitbart/coBench/commands"
+	"github.com/jbreitbart/coBench/stats"
+	log "github.com/sirupsen/logrus"
+)
+
+// aggregations of cobench query, ci is printed as two columns
+var queryAggregations = map[string]func(q *stats.QueryResultT) []float64{
+	"n":      func(q *stats.QueryResultT) []float64 { return []float64{float64(len(q.Values))} },
+	"mean":   func(q *stats.QueryResultT) []float64 { return []float64{q.Mean} },
+	"stddev": func(q *stats.QueryResultT) []float64 { return []float64{q.Stddev} },
+	"median": func(q *stats.QueryResultT) []float64 { return []float64{q.Median} },
+	"p25":    func(q *stats.QueryResultT) []float64 { return []float64{q.P25} },
+	"p75":    func(q *stats.QueryResultT) []float64 { return []float64{q.P75} },
+	"min":    func(q *stats.QueryResultT) []float64 { return []float64{q.Min} },
+	"max":    func(q *stats.QueryResultT) []float64 { return []float64{q.Max} },
+	"ci":     func(q *stats.QueryResultT) []float64 { return []float64{q.CI[0], q.CI[1]} },
+}
+
+func queryColumns(aggs []string) []string {
+	ret := []string{"file", "app", "corunner", "cat_bits"}
+	for _, a := range aggs {
+		if a == "ci" {
+			ret = append(ret, "ci_low", "ci_high")
+		} else {
+			ret = append(ret, a)
+		}
+	}
+	return ret
+}
+
+func parseQueryFilter(app string, corunner string, cat string, mask string) (stats.FilterT, error) {
+	f := stats.FilterT{App: app, CoSched: corunner}
+	switch cat {
+	case "":
+	case "none":
+		bits := stats.NoCATMask
+		f.CATBits = &bits
+	default:
+		bits, err := strconv.Atoi(cat)
+		if err != nil || bits <= 0 {
+			return f, fmt.Errorf("Invalid number of CAT bits %v", cat)
+		}
+		f.CATBits = &bits
+	}
+	if mask != "" {
+		m, err := strconv.ParseUint(strings.TrimPrefix(mask, "0x"), 16, 64)
+		if err != nil {
+			return f, fmt.Errorf("Invalid CAT mask %v", mask)
+		}
+		f.CATMask = &m
+	}
+	return f, nil
+}
+
+// cobench query [options] a.json ...
+func queryMain(args []string) {
+	flags := flag.NewFlagSet("query", flag.ExitOnError)
+	app := flags.String("app", "", "Only this application, the command or its name, e.g. bt")
+	corunner := flags.String("corunner", "", "Only runs co-scheduled with this application, or none for the individual runs")
+	cat := flags.String("cat", "", "Only runs with this number of CAT bits (ways), or none for the runs without CAT")
+	mask := flags.String("mask", "", "Only runs with this CAT mask in hex, e.g. 0x3f")
+	metric := flags.String("metric", stats.RuntimeMetric, "Metric to aggregate")
+	agg := flags.String("agg", "n,mean,stddev,median,ci", "Comma separated aggregations: n, mean, stddev, median, p25, p75, min, max, ci")
+	ciLevel := flags.Float64("ci-level", stats.DefaultCILevel, "Confidence level of the confidence interval of the mean")
+	format := flags.String("format", "table", "Output format: table, csv or json")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: cobench query [options] a.json ...")
+		fmt.Fprintln(flags.Output(), "Filters the configurations of the result files and prints aggregates of a metric.")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	filter, err := parseQueryFilter(*app, *corunner, *cat, *mask)
+	if err != nil {
+		log.WithError(err).Fatalln("Invalid filter")
+	}
+	aggs := strings.Split(*agg, ",")
+	for _, a := range aggs {
+		if _, exists := queryAggregations[a]; !exists {
+			log.WithField("aggregation", a).Fatalln("Unknown aggregation")
+		}
+	}
+
+	var rows [][]interface{}
+	for _, filename := range flags.Args() {
+		d, err := stats.ReadDataset(filename)
+		if err != nil {
+			log.WithError(err).WithField("file", filename).Fatalln("Cannot read input file")
+		}
+		for _, q := range stats.Query(d, filter, *metric, *ciLevel) {
+			corunner := ""
+			if q.Config.CoSched != "" {
+				corunner = commands.Pretty(q.Config.CoSched)
+			}
+			row := []interface{}{filename, commands.Pretty(q.Config.App), corunner, q.Config.CATBits}
+			for _, a := range aggs {
+				for _, v := range queryAggregations[a](&q) {
+					if a == "n" {
+						row = append(row, int(v))
+					} else {
+						row = append(row, v)
+					}
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	columns := queryColumns(aggs)
+	switch *format {
+	case "table":
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(columns, "\t"))
+		for _, row := range rows {
+			fmt.Fprintln(w, strings.Join(queryRowToStrings(row, "%1.4f"), "\t"))
+		}
+		w.Flush()
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write(columns)
+		for _, row := range rows {
+			w.Write(queryRowToStrings(row, ""))
+		}
+		w.Flush()
+		err = w.Error()
+	case "json":
+		out := make([]map[string]interface{}, 0, len(rows))
+		for _, row := range rows {
+			o := make(map[string]interface{})
+			for i, v := range row {
+				// NaN is not valid in JSON
+				if f, ok := v.(float64); ok && math.IsNaN(f) {
+					v = nil
+				}
+				o[columns[i]] = v
+			}
+			out = append(out, o)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(out)
+	default:
+		log.WithField("format", *format).Fatalln("Unknown output format")
+	}
+	if err != nil {
+		log.WithError(err).Fatalln("Error while write query result")
+	}
+}
+
+// floats are formatted with format or with the shortest representation if format is empty
+func queryRowToStrings(row []interface{}, format string) []string {
+	var ret []string
+	for _, v := range row {
+		f, ok := v.(float64)
+		switch {
+		case ok && format != "":
+			ret = append(ret, fmt.Sprintf(format, f))
+		case ok:
+			ret = append(ret, strconv.FormatFloat(f, 'g', -1, 64))
+		default:
+			ret = append(ret, fmt.Sprintf("%v", v))
+		}
+	}
+	return ret
+}
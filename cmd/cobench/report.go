@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jbreitbart/coBench/analysis"
+	log "github.com/sirupsen/logrus"
+)
+
+// cobench report [options] result.json
+func reportMain(args []string) {
+	opts := analysis.DefaultOptions()
+
+	flags := flag.NewFlagSet("report", flag.ExitOnError)
+	flags.StringVar(&opts.Report, "output", "report.html", "HTML report file")
+	shared := addAnalysisFlags(flags, &opts)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: cobench report [options] result.json")
+		fmt.Fprintln(flags.Output(), "Writes a self-contained HTML report with the plots, slowdown matrices and raw runs of a result file.")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	if err := newAnalyzer(flags.Arg(0), shared, &opts).Report(flags.Arg(0)); err != nil {
+		log.WithError(err).Fatalln("Cannot write report")
+	}
+}
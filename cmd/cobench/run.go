@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jbreitbart/coBench/cgroup"
+	"github.com/jbreitbart/coBench/commands"
+	"github.com/jbreitbart/coBench/launcher"
+	"github.com/jbreitbart/coBench/runner"
+	"github.com/jbreitbart/coBench/stats"
+	"github.com/multiplay/go-slack/chat"
+	"github.com/multiplay/go-slack/lrhook"
+	log "github.com/sirupsen/logrus"
+)
+
+// cobench run [options]
+func runMain(args []string) {
+	opts := runner.DefaultOptions()
+
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	flags.IntVar(&opts.Runs, "runs", opts.Runs, "Number of times the applications are executed")
+	commandFile := flags.String("cmd", "cmd.txt", "Text file containing the commands to execute")
+
+	flags.StringVar(&opts.CPUs[0], "cpus0", opts.CPUs[0], "List of CPUs to be used for the 1st command")
+	flags.StringVar(&opts.CPUs[1], "cpus1", opts.CPUs[1], "List of CPUs to be used for the 2nd command")
+	flags.StringVar(&opts.Threads, "threads", opts.Threads, "Number of threads to be used")
+
+	flags.BoolVar(&opts.CAT, "cat", false, "Measure with all CAT settings")
+	flags.BoolVar(&opts.InverseCAT, "cat-inverse", false, "Inverse the CAT masks")
+	flags.Uint64Var(&opts.CATChunk, "catChunk", opts.CATChunk, "Bits changed from one run to the next")
+	flags.StringVar(&opts.ResctrlPath, "resctrl", opts.ResctrlPath, "Root path of the resctrl file system")
+
+	flags.StringVar(&opts.CgroupRoot, "cgroup-root", "", "cgroup v2 group in which a group per CPU list is created, e.g. /sys/fs/cgroup/cobench. "+
+		"Every run is executed in its own group and its memory.peak, cpu.stat and pressure are stored. Disabled by default")
+	cgroupSweep := flags.String("cgroup-sweep", "", "JSON file containing cgroup settings measured after the CAT settings, e.g. "+
+		`[{"Name": "mem-1G", "Limits": [{"memory.max": "1G"}, {"io.max": "8:0 rbps=1048576"}]}]. Requires -cgroup-root`)
+
+	launcherSpec := flags.String("launcher", "shell", "Launcher of the applications: "+strings.Join(launcher.Names(), ", ")+
+		". Parameters follow the name separated by spaces, e.g. \"numactl membind=0\". A line of the command file can select its own launcher with a [launcher] prefix")
+	hermitcore := flags.Bool("hermitcore", false, "Use if you are executing hermitcore binaries. Same as -launcher hermitcore")
+
+	flags.Float64Var(&opts.VarianceDiff, "variance", opts.VarianceDiff, "Minimum differences in variance required between runs")
+	flags.Float64Var(&opts.CIWidth, "ci", opts.CIWidth, "Stop once the confidence interval of the mean runtime is within ±ci of the mean (e.g. 0.01 for ±1%). Replaces -variance")
+	flags.Float64Var(&opts.CILevel, "ci-level", opts.CILevel, "Confidence level of the confidence interval")
+	flags.IntVar(&opts.MaxRuns, "max-runs", 0, "Maximum number of times the applications are executed. 0 means no limit")
+	flags.IntVar(&opts.WarmupRuns, "warmup", 0, "Number of runs executed before the measurements start. They are stored, but excluded from the statistics")
+	flags.StringVar(&opts.OutlierMethod, "outlier", opts.OutlierMethod, "Outlier detection excluding runs from the statistics: iqr or mad. Disabled by default")
+	flags.Float64Var(&opts.OutlierThreshold, "outlier-threshold", opts.OutlierThreshold, "Threshold of the outlier detection. Defaults to 1.5 for iqr and 3.5 for mad")
+
+	flags.BoolVar(&opts.NoCoSched, "no-cosched", false, "Disable co-scheduling")
+	flags.BoolVar(&opts.NoIndvSched, "no-indv", false, "Disable the individual runs")
+
+	flags.StringVar(&opts.PerfStat, "pstat", "", "If set commands are with perf stat -e <param>. Param could be intel_cqm/llc_occupancy/,LLC-load-misses")
+	flags.IntVar(&opts.PerfInterval, "pstat-interval", 0, "If > 0 perf stat samples the counters every <ms> milliseconds (perf stat -I <ms>) and stores the time series")
+
+	metricRules := flags.String("metrics", "", "JSON file containing rules to extract metrics reported by the applications from their output")
+	flags.StringVar(&opts.SlowdownMetric, "slowdown-metric", opts.SlowdownMetric, "Metric used to compute the slowdown")
+
+	flags.StringVar(&opts.Output, "output", time.Now().Format("06-01-02-15-04-05.result.json"), "Name of the result json file")
+
+	slackChannel := flags.String("slack-channel", "#cobench", "The channel coBench will use for logging")
+	slackWebhook := flags.String("slack-webhook", "", "The webhook of your slack application")
+	slackLevel := flags.Int("slack-level", 1, "Select the lowest log level forwarded to slack. 0: Debug; 1: Info; 2: Warn")
+
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: cobench run [options]")
+		fmt.Fprintln(flags.Output(), "Executes every command of -cmd individually and every pair of commands co-scheduled and stores the runtimes in -output.")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	if *slackWebhook != "" {
+		cfg := lrhook.Config{
+			MinLevel: log.InfoLevel,
+			Message: chat.Message{
+				Channel:   *slackChannel,
+				IconEmoji: ":ghost:",
+			},
+		}
+		if *slackLevel == 0 {
+			cfg.MinLevel = log.DebugLevel
+		}
+		if *slackLevel == 2 {
+			cfg.MinLevel = log.WarnLevel
+		}
+		h := lrhook.New(cfg, *slackWebhook)
+		log.AddHook(h)
+	}
+
+	if *metricRules != "" {
+		var err error
+		if opts.MetricRules, err = stats.ReadMetricRules(*metricRules); err != nil {
+			log.WithError(err).WithField("file", *metricRules).Fatalln("Could not read metric rules")
+		}
+	}
+
+	if *cgroupSweep != "" {
+		var err error
+		if opts.Cgroups, err = cgroup.ReadSweep(*cgroupSweep); err != nil {
+			log.WithError(err).WithField("file", *cgroupSweep).Fatalln("Could not read cgroup settings")
+		}
+	}
+
+	if *hermitcore {
+		*launcherSpec = "hermitcore"
+	}
+	l, err := launcher.Parse(*launcherSpec)
+	if err != nil {
+		log.WithError(err).Fatalln("Invalid launcher")
+	}
+	opts.Launcher = l
+
+	lines, err := commands.Read(*commandFile)
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"file": *commandFile,
+		}).Fatalln("Could not read command file")
+	}
+	commandStrings, launchers, err := splitLaunchers(lines)
+	if err != nil {
+		log.WithError(err).WithField("file", *commandFile).Fatalln("Invalid launcher in command file")
+	}
+	opts.Launchers = launchers
+
+	r, err := runner.New(opts)
+	if err != nil {
+		log.WithError(err).Fatalln("Invalid options")
+	}
+
+	// the runs measured so far are stored on SIGINT or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err = r.Run(ctx, commandStrings); err != nil {
+		log.WithError(err).Fatalln("Benchmark failed")
+	}
+}
+
+// removes the launcher prefixes from the lines of the command file and returns the commands with their launchers
+func splitLaunchers(lines []string) ([]string, map[string]launcher.LauncherT, error) {
+	var commandStrings []string
+	launchers := make(map[string]launcher.LauncherT)
+	for _, line := range lines {
+		spec, c := launcher.SplitCommand(line)
+		commandStrings = append(commandStrings, c)
+		if spec == "" {
+			continue
+		}
+
+		l, err := launcher.Parse(spec)
+		if err != nil {
+			return nil, nil, err
+		}
+		if old, exists := launchers[c]; exists && old.String() != l.String() {
+			return nil, nil, fmt.Errorf("Command %v uses the launchers %v and %v", c, old, l)
+		}
+		launchers[c] = l
+	}
+	return commandStrings, launchers, nil
+}
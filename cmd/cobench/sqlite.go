@@ -0,0 +1,7 @@
+//go:build sqlite
+// +build sqlite
+
+package main
+
+// stores results in .db and .sqlite files
+import _ "github.com/jbreitbart/coBench/stats/sqlite"
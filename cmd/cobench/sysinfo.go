@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"text/tabwriter"
+
+	"github.com/jbreitbart/coBench/runner"
+)
+
+// cobench sysinfo [options]
+func sysinfoMain(args []string) {
+	flags := flag.NewFlagSet("sysinfo", flag.ExitOnError)
+	resctrlPath := flags.String("resctrl", runner.DefaultOptions().ResctrlPath, "Root path of the resctrl file system")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: cobench sysinfo [options]")
+		fmt.Fprintln(flags.Output(), "Prints the CPUs, the L3 cache and the CAT support as stored in the result files.")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	hostname, _ := os.Hostname()
+	hw := runner.DetectHardware("")
+	minBits, numBits, err := runner.ReadCATInfo(*resctrlPath)
+	cat := "yes"
+	if err != nil {
+		cat = "no"
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Host\t%v\n", hostname)
+	fmt.Fprintf(w, "CPUs\t%v\n", runtime.NumCPU())
+	fmt.Fprintf(w, "L3 size (bytes)\t%v\n", hw.L3Size)
+	fmt.Fprintf(w, "L3 ways\t%v\n", hw.L3Ways)
+	fmt.Fprintf(w, "L3 caches\t%v\n", hw.L3Caches)
+	fmt.Fprintf(w, "CAT\t%v\n", cat)
+	fmt.Fprintf(w, "CAT bits\t%v\n", numBits)
+	fmt.Fprintf(w, "CAT min bits\t%v\n", minBits)
+	w.Flush()
+}
@@ -0,0 +1,92 @@
+package launcher
+
+import (
+	"fmt"
+)
+
+// executes the command with /bin/sh, the OpenMP runtime pins the threads
+type shellLauncher struct{}
+
+func (shellLauncher) String() string {
+	return "shell"
+}
+
+func (shellLauncher) Command(t TargetT) ([]string, []string, error) {
+	return shellArgs(t.Command), openMPEnv(t), nil
+}
+
+// binds the shell and the application to the CPUs with taskset
+type tasksetLauncher struct{}
+
+func (tasksetLauncher) String() string {
+	return "taskset"
+}
+
+func (tasksetLauncher) Command(t TargetT) ([]string, []string, error) {
+	args := append([]string{"taskset", "-c", t.CPUs}, shellArgs(t.Command)...)
+	return args, openMPEnv(t), nil
+}
+
+// binds the CPUs and the memory with numactl. Without a memory policy memory is allocated on the local node.
+type numactlLauncher struct {
+	params map[string]string
+}
+
+// numactl memory policies accepted as parameters
+var numactlPolicies = []string{"membind", "interleave", "preferred"}
+
+func newNumactl(params string) (LauncherT, error) {
+	defaults := make(map[string]string)
+	for _, p := range numactlPolicies {
+		defaults[p] = ""
+	}
+	p, err := parseParams("numactl", params, defaults)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := 0
+	for _, policy := range numactlPolicies {
+		if p[policy] != "" {
+			policies++
+		}
+	}
+	if policies > 1 {
+		return nil, fmt.Errorf("Launcher numactl accepts only one of the memory policies %v", numactlPolicies)
+	}
+
+	return &numactlLauncher{params: p}, nil
+}
+
+func (l *numactlLauncher) String() string {
+	return specString("numactl", l.params)
+}
+
+func (l *numactlLauncher) Command(t TargetT) ([]string, []string, error) {
+	args := []string{"numactl", "--physcpubind=" + t.CPUs}
+	memory := "--localalloc"
+	for _, policy := range numactlPolicies {
+		if l.params[policy] != "" {
+			memory = "--" + policy + "=" + l.params[policy]
+		}
+	}
+	args = append(args, memory)
+	args = append(args, shellArgs(t.Command)...)
+	return args, openMPEnv(t), nil
+}
+
+func init() {
+	Register("shell", func(params string) (LauncherT, error) {
+		if params != "" {
+			return nil, fmt.Errorf("Launcher shell has no parameters")
+		}
+		return shellLauncher{}, nil
+	})
+	Register("taskset", func(params string) (LauncherT, error) {
+		if params != "" {
+			return nil, fmt.Errorf("Launcher taskset has no parameters")
+		}
+		return tasksetLauncher{}, nil
+	})
+	Register("numactl", newNumactl)
+}
@@ -0,0 +1,174 @@
+package launcher
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// maximum time between the start of the runtime CLI and the start of the container
+const containerStartTimeout = 30 * time.Second
+
+// number of containers started, part of the container names
+var containers uint64
+
+// executes the command with /bin/sh in a container of a docker or podman compatible runtime CLI. The container is
+// limited to the CPUs with --cpuset-cpus. With CAT or a cgroup the container waits on its stdin until its init process
+// was moved into the resctrl group and the cgroup, every process it starts inherits the groups. The cgroup limits the
+// process to the CPUs of its slot as well.
+type containerLauncher struct {
+	params map[string]string
+	// options of the runtime, one per option parameter, e.g. option=--mount=type=bind,src=/data,dst=/data
+	options []string
+}
+
+func newContainer(params string) (LauncherT, error) {
+	// option may be given multiple times, its values can contain any character but a space
+	var options []string
+	var others []string
+	for _, p := range strings.Fields(params) {
+		if strings.HasPrefix(p, "option=") {
+			options = append(options, strings.TrimPrefix(p, "option="))
+		} else {
+			others = append(others, p)
+		}
+	}
+
+	p, err := parseParams("container", strings.Join(others, " "), map[string]string{"runtime": "docker", "image": ""})
+	if err != nil {
+		return nil, err
+	}
+	if p["image"] == "" {
+		return nil, fmt.Errorf("Launcher container requires an image")
+	}
+	return &containerLauncher{params: p, options: options}, nil
+}
+
+func (l *containerLauncher) String() string {
+	ret := specString("container", l.params)
+	for _, o := range l.options {
+		ret += " option=" + o
+	}
+	return ret
+}
+
+// runtime run [options] image /bin/sh -c command. Start relies on run being the 2nd argument.
+func (l *containerLauncher) Command(t TargetT) ([]string, []string, error) {
+	args := []string{l.params["runtime"], "run", "--rm", "--cpuset-cpus=" + t.CPUs}
+	for _, env := range openMPEnv(t) {
+		args = append(args, "-e", env)
+	}
+	args = append(args, l.options...)
+
+	command := t.Command
+	if t.ResctrlGroup != "" || t.Cgroup != "" {
+		args = append(args, "-i")
+		command = "read _; " + command
+	}
+	args = append(args, l.params["image"])
+	args = append(args, shellArgs(command)...)
+	return args, nil, nil
+}
+
+// Start names the container to find its init process and to stop it on cancellation. With a resctrl group or a cgroup
+// the init process is moved into the groups before it starts the command.
+func (l *containerLauncher) Start(cmd *exec.Cmd, t TargetT) error {
+	if len(cmd.Args) < 2 || cmd.Args[1] != "run" {
+		return fmt.Errorf("Command of launcher container expected, got %v", cmd.Args)
+	}
+	name := fmt.Sprintf("cobench-%d-%d", os.Getpid(), atomic.AddUint64(&containers, 1))
+	cmd.Args = append(cmd.Args[:2:2], append([]string{"--name=" + name}, cmd.Args[2:]...)...)
+
+	kill := func() {
+		runtimeCommand(cmd, "kill", name).Run()
+	}
+	// set by exec.CommandContext
+	if cancel := cmd.Cancel; cancel != nil {
+		cmd.Cancel = func() error {
+			kill()
+			return cancel()
+		}
+	}
+
+	if t.ResctrlGroup == "" && t.Cgroup == "" {
+		return cmd.Start()
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err = cmd.Start(); err != nil {
+		return err
+	}
+
+	err = l.moveToGroups(cmd, name, t)
+	if err == nil {
+		_, err = stdin.Write([]byte("\n"))
+	}
+	stdin.Close()
+	if err != nil {
+		kill()
+		cmd.Process.Kill()
+		cmd.Wait()
+		return err
+	}
+	return nil
+}
+
+// another command of the runtime CLI of cmd with the same environment, e.g. DOCKER_HOST
+func runtimeCommand(cmd *exec.Cmd, args ...string) *exec.Cmd {
+	ret := exec.Command(cmd.Path, args...)
+	ret.Env = cmd.Env
+	return ret
+}
+
+// waits for the init process of the container and writes it to the tasks of the resctrl group and the processes of the
+// cgroup. The cgroup of the runtime is left, the CPUs of the container are limited by the cpuset of the slot group.
+func (l *containerLauncher) moveToGroups(cmd *exec.Cmd, name string, t TargetT) error {
+	var pid int
+	for start := time.Now(); pid == 0; time.Sleep(10 * time.Millisecond) {
+		if time.Since(start) > containerStartTimeout {
+			return fmt.Errorf("Container %v did not start within %v", name, containerStartTimeout)
+		}
+		out, err := runtimeCommand(cmd, "inspect", "--format", "{{.State.Pid}}", name).Output()
+		if err != nil {
+			// not created yet
+			continue
+		}
+		pid, _ = strconv.Atoi(strings.TrimSpace(string(out)))
+	}
+
+	if t.ResctrlGroup != "" {
+		file, err := os.OpenFile(t.ResctrlGroup+"/tasks", os.O_WRONLY, 0)
+		if err != nil {
+			return fmt.Errorf("CAT could not open tasks file: %v", err)
+		}
+		defer file.Close()
+
+		if _, err = file.WriteString(strconv.Itoa(pid)); err != nil {
+			return fmt.Errorf("CAT could not write container %v to tasks file: %v", name, err)
+		}
+	}
+
+	if t.Cgroup != "" {
+		file, err := os.OpenFile(t.Cgroup+"/cgroup.procs", os.O_WRONLY, 0)
+		if err != nil {
+			return fmt.Errorf("cgroup could not open cgroup.procs: %v", err)
+		}
+		defer file.Close()
+
+		if _, err = file.WriteString(strconv.Itoa(pid)); err != nil {
+			return fmt.Errorf("cgroup could not write container %v to cgroup.procs: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func init() {
+	Register("container", newContainer)
+}
@@ -0,0 +1,28 @@
+package launcher
+
+// executes HermitCore binaries. The CPUs are bound with numactl, the isle and its memory are configurable.
+type hermitcoreLauncher struct {
+	params map[string]string
+}
+
+func newHermitcore(params string) (LauncherT, error) {
+	p, err := parseParams("hermitcore", params, map[string]string{"isle": "uhyve", "mem": "4G"})
+	if err != nil {
+		return nil, err
+	}
+	return &hermitcoreLauncher{params: p}, nil
+}
+
+func (l *hermitcoreLauncher) String() string {
+	return specString("hermitcore", l.params)
+}
+
+func (l *hermitcoreLauncher) Command(t TargetT) ([]string, []string, error) {
+	args := append([]string{"numactl", "--physcpubind=" + t.CPUs}, shellArgs(t.Command)...)
+	env := []string{"HERMIT_CPUS=" + t.Threads, "HERMIT_MEM=" + l.params["mem"], "HERMIT_ISLE=" + l.params["isle"]}
+	return args, env, nil
+}
+
+func init() {
+	Register("hermitcore", newHermitcore)
+}
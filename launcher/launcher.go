@@ -0,0 +1,141 @@
+// Package launcher builds the command line, the CPU affinity and the environment of the applications executed by
+// coBench.
+//
+// A launcher is selected by a specification: its name, optionally followed by parameters separated by spaces, e.g.
+// "shell", "numactl membind=0" or "hermitcore isle=qemu mem=8G".
+package launcher
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// TargetT is an application to launch
+type TargetT struct {
+	// shell command of the application
+	Command string
+	// CPU list the application is bound to, e.g. 0-4
+	CPUs string
+	// number of threads the application should use
+	Threads string
+	// resctrl group of the CPUs, empty without CAT. Applications started on the CPUs are in this group.
+	ResctrlGroup string
+	// cgroup v2 group of the run, empty without cgroups. Commands of launchers implementing StarterT are not moved into
+	// the group by coBench.
+	Cgroup string
+}
+
+// LauncherT builds the execution of an application
+type LauncherT interface {
+	// String returns the specification of the launcher
+	String() string
+	// Command returns the program with its arguments and the environment variables added to the environment of coBench
+	Command(t TargetT) (args []string, env []string, err error)
+}
+
+// StarterT is implemented by launchers whose applications are not started as children of the launched program, e.g.
+// containers. They have to move the application into its resctrl group and its cgroup themselves.
+type StarterT interface {
+	// Start starts cmd as built from Command(t) and returns once the application runs as described by t. The caller
+	// waits for cmd.
+	Start(cmd *exec.Cmd, t TargetT) error
+}
+
+// FactoryT creates a launcher from the parameters of a specification, i.e. everything after the name
+type FactoryT func(params string) (LauncherT, error)
+
+var factories = make(map[string]FactoryT)
+
+// Register makes a launcher available by name
+func Register(name string, f FactoryT) {
+	factories[name] = f
+}
+
+// Names returns the names of all launchers sorted
+func Names() []string {
+	var ret []string
+	for name := range factories {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Parse returns the launcher of a specification
+func Parse(spec string) (LauncherT, error) {
+	spec = strings.TrimSpace(spec)
+	name, params := spec, ""
+	if i := strings.IndexAny(spec, " \t"); i != -1 {
+		name, params = spec[:i], strings.TrimSpace(spec[i+1:])
+	}
+
+	f, exists := factories[name]
+	if !exists {
+		return nil, fmt.Errorf("Unknown launcher %v. Available: %v", name, strings.Join(Names(), ", "))
+	}
+	return f(params)
+}
+
+// SplitCommand splits an optional launcher specification in square brackets from the start of a line of the command
+// file, e.g. "[numactl membind=1] ./app". spec is empty if the line has no specification.
+func SplitCommand(line string) (spec string, command string) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "[") {
+		return "", line
+	}
+	end := strings.Index(trimmed, "]")
+	if end == -1 {
+		return "", line
+	}
+	return strings.TrimSpace(trimmed[1:end]), strings.TrimSpace(trimmed[end+1:])
+}
+
+// parses parameters of the form key=value separated by spaces. Only the keys in defaults are accepted, missing keys
+// have the default value.
+func parseParams(name string, params string, defaults map[string]string) (map[string]string, error) {
+	ret := make(map[string]string)
+	for k, v := range defaults {
+		ret[k] = v
+	}
+
+	for _, p := range strings.Fields(params) {
+		kv := strings.SplitN(p, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("Invalid parameter %v of launcher %v, expected key=value", p, name)
+		}
+		if _, exists := defaults[kv[0]]; !exists {
+			return nil, fmt.Errorf("Unknown parameter %v of launcher %v", kv[0], name)
+		}
+		ret[kv[0]] = kv[1]
+	}
+	return ret, nil
+}
+
+// spec of a launcher with its parameters, sorted by key for a stable result file
+func specString(name string, params map[string]string) string {
+	var keys []string
+	for k, v := range params {
+		if v != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	ret := name
+	for _, k := range keys {
+		ret += " " + k + "=" + params[k]
+	}
+	return ret
+}
+
+// shell command line executing the command with /bin/sh
+func shellArgs(command string) []string {
+	return []string{"/bin/sh", "-c", command}
+}
+
+// environment of OpenMP applications
+func openMPEnv(t TargetT) []string {
+	return []string{"GOMP_CPU_AFFINITY=" + t.CPUs, "OMP_NUM_THREADS=" + t.Threads}
+}
@@ -0,0 +1,236 @@
+package launcher
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommand(t *testing.T) {
+	target := TargetT{Command: "./app -n 1", CPUs: "0-3", Threads: "4"}
+	omp := []string{"GOMP_CPU_AFFINITY=0-3", "OMP_NUM_THREADS=4"}
+
+	expected := []struct {
+		spec string
+		args []string
+		env  []string
+	}{
+		{"shell", []string{"/bin/sh", "-c", "./app -n 1"}, omp},
+		{"taskset", []string{"taskset", "-c", "0-3", "/bin/sh", "-c", "./app -n 1"}, omp},
+		{"numactl", []string{"numactl", "--physcpubind=0-3", "--localalloc", "/bin/sh", "-c", "./app -n 1"}, omp},
+		{"numactl membind=0,1", []string{"numactl", "--physcpubind=0-3", "--membind=0,1", "/bin/sh", "-c", "./app -n 1"}, omp},
+		{"hermitcore", []string{"numactl", "--physcpubind=0-3", "/bin/sh", "-c", "./app -n 1"},
+			[]string{"HERMIT_CPUS=4", "HERMIT_MEM=4G", "HERMIT_ISLE=uhyve"}},
+		{"hermitcore isle=qemu mem=8G", []string{"numactl", "--physcpubind=0-3", "/bin/sh", "-c", "./app -n 1"},
+			[]string{"HERMIT_CPUS=4", "HERMIT_MEM=8G", "HERMIT_ISLE=qemu"}},
+		{"wrapper srun --cpu-bind=map_cpu:{{.CPUs}} sh -c {{quote .Command}}",
+			[]string{"/bin/sh", "-c", "srun --cpu-bind=map_cpu:0-3 sh -c './app -n 1'"}, omp},
+	}
+
+	for _, e := range expected {
+		l, err := Parse(e.spec)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", e.spec, err)
+			continue
+		}
+		args, env, err := l.Command(target)
+		if err != nil {
+			t.Errorf("%v: Command failed: %v", e.spec, err)
+			continue
+		}
+		if !reflect.DeepEqual(args, e.args) || !reflect.DeepEqual(env, e.env) {
+			t.Errorf("%v: Command = %q, %q, expected %q, %q", e.spec, args, env, e.args, e.env)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	expected := map[string]string{
+		"shell":                     "shell",
+		" numactl  interleave=all ": "numactl interleave=all",
+		"hermitcore mem=8G":         "hermitcore isle=uhyve mem=8G",
+		"wrapper echo {{.Command}}": "wrapper echo {{.Command}}",
+	}
+
+	for spec, e := range expected {
+		l, err := Parse(spec)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", spec, err)
+		}
+		if s := l.String(); s != e {
+			t.Errorf("Parse(%q).String() = %q, expected %q", spec, s, e)
+		}
+		// the specification of a launcher parses to the same launcher
+		if again, err := Parse(l.String()); err != nil || again.String() != e {
+			t.Errorf("Parse(%q) = %v, %v", l.String(), again, err)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{"unknown", "shell x=1", "numactl membind", "numactl cpus=1", "numactl membind=0 interleave=1", "wrapper", "wrapper {{.Command"} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) succeeded", spec)
+		}
+	}
+}
+
+func TestSplitCommand(t *testing.T) {
+	expected := []struct {
+		line, spec, command string
+	}{
+		{"./app 1", "", "./app 1"},
+		{"[numactl membind=1] ./app 1", "numactl membind=1", "./app 1"},
+		{"  [ taskset ]./app", "taskset", "./app"},
+		{"[broken ./app", "", "[broken ./app"},
+	}
+
+	for _, e := range expected {
+		if spec, command := SplitCommand(e.line); spec != e.spec || command != e.command {
+			t.Errorf("SplitCommand(%q) = %q, %q, expected %q, %q", e.line, spec, command, e.spec, e.command)
+		}
+	}
+}
+
+// the wrapper quotes commands with quotes for the shell
+func TestWrapperQuote(t *testing.T) {
+	l, err := Parse("wrapper sh -c {{quote .Command}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	args, _, err := l.Command(TargetT{Command: `echo "it's" $((1+1))`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command(args[0], args[1:]...).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "it's 2\n" {
+		t.Errorf("Wrapped command printed %q", out)
+	}
+}
+
+func TestContainerCommand(t *testing.T) {
+	l, err := Parse("container image=alpine option=--network=host option=--mount=type=bind,src=/data,dst=/data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := TargetT{Command: "./app", CPUs: "0-3", Threads: "4"}
+
+	args, _, err := l.Command(target)
+	expected := []string{"docker", "run", "--rm", "--cpuset-cpus=0-3", "-e", "GOMP_CPU_AFFINITY=0-3", "-e", "OMP_NUM_THREADS=4",
+		"--network=host", "--mount=type=bind,src=/data,dst=/data", "alpine", "/bin/sh", "-c", "./app"}
+	if err != nil || !reflect.DeepEqual(args, expected) {
+		t.Errorf("Command = %q, %v, expected %q", args, err, expected)
+	}
+
+	// waits until it was moved into the resctrl group
+	target.ResctrlGroup = "/sys/fs/resctrl/cobench0"
+	args, _, err = l.Command(target)
+	expected = []string{"docker", "run", "--rm", "--cpuset-cpus=0-3", "-e", "GOMP_CPU_AFFINITY=0-3", "-e", "OMP_NUM_THREADS=4",
+		"--network=host", "--mount=type=bind,src=/data,dst=/data", "-i", "alpine", "/bin/sh", "-c", "read _; ./app"}
+	if err != nil || !reflect.DeepEqual(args, expected) {
+		t.Errorf("Command = %q, %v, expected %q", args, err, expected)
+	}
+
+	if s := l.String(); s != "container image=alpine runtime=docker option=--network=host option=--mount=type=bind,src=/data,dst=/data" {
+		t.Errorf("String = %q", s)
+	}
+
+	if _, err := Parse("container runtime=podman"); err == nil {
+		t.Errorf("Container without image accepted")
+	}
+}
+
+// creates the command of the container launcher with the fake runtime. Returns the command and the state directory
+// of the runtime.
+func fakeContainer(t *testing.T, ctx context.Context, target TargetT) (*exec.Cmd, LauncherT, string) {
+	runtime, err := filepath.Abs("testdata/fake-runtime")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := Parse("container runtime=" + runtime + " image=test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	args, env, err := l.Command(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state := t.TempDir()
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Env = append(append(os.Environ(), env...), "FAKE_RUNTIME_STATE="+state)
+	return cmd, l, state
+}
+
+func TestContainerStart(t *testing.T) {
+	group := t.TempDir()
+	if err := ioutil.WriteFile(group+"/tasks", nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	cgroup := t.TempDir()
+	if err := ioutil.WriteFile(cgroup+"/cgroup.procs", nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	target := TargetT{Command: "echo $OMP_NUM_THREADS; exit 3", CPUs: "1,3", Threads: "2", ResctrlGroup: group, Cgroup: cgroup}
+	cmd, l, state := fakeContainer(t, context.Background(), target)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+
+	if err := l.(StarterT).Start(cmd, target); err != nil {
+		t.Fatal(err)
+	}
+	err := cmd.Wait()
+	if exit, ok := err.(*exec.ExitError); !ok || exit.ExitCode() != 3 {
+		t.Errorf("Container exited with %v, expected exit status 3", err)
+	}
+	if out.String() != "2\n" {
+		t.Errorf("Container printed %q", out.String())
+	}
+
+	pids, _ := filepath.Glob(state + "/*.pid")
+	if len(pids) != 1 {
+		t.Fatalf("Found containers %v", pids)
+	}
+	pid, _ := ioutil.ReadFile(pids[0])
+	tasks, _ := ioutil.ReadFile(group + "/tasks")
+	if strings.TrimSpace(string(pid)) != string(tasks) {
+		t.Errorf("tasks contains %q, expected the pid %q", tasks, pid)
+	}
+	procs, _ := ioutil.ReadFile(cgroup + "/cgroup.procs")
+	if strings.TrimSpace(string(pid)) != string(procs) {
+		t.Errorf("cgroup.procs contains %q, expected the pid %q", procs, pid)
+	}
+	cpus, _ := ioutil.ReadFile(strings.TrimSuffix(pids[0], ".pid") + ".cpus")
+	if string(cpus) != "1,3\n" {
+		t.Errorf("Container uses the CPUs %q", cpus)
+	}
+}
+
+func TestContainerCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	target := TargetT{Command: "sleep 20", CPUs: "0", Threads: "1"}
+	cmd, l, _ := fakeContainer(t, ctx, target)
+
+	start := time.Now()
+	if err := l.(StarterT).Start(cmd, target); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	if err := cmd.Wait(); err == nil {
+		t.Errorf("Cancelled container succeeded")
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("Cancelled container took %v", d)
+	}
+}
@@ -0,0 +1,47 @@
+package launcher
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// executes a shell command line created from a template with the fields of TargetT, e.g.
+// "srun --cpu-bind=map_cpu:{{.CPUs}} sh -c {{quote .Command}}". quote quotes a string for the shell.
+type wrapperLauncher struct {
+	text     string
+	template *template.Template
+}
+
+// quotes s in single quotes for /bin/sh
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+func newWrapper(params string) (LauncherT, error) {
+	if params == "" {
+		return nil, fmt.Errorf("Launcher wrapper requires a template")
+	}
+	tmpl, err := template.New("wrapper").Funcs(template.FuncMap{"quote": shellQuote}).Option("missingkey=error").Parse(params)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid template of launcher wrapper: %v", err)
+	}
+	return &wrapperLauncher{text: params, template: tmpl}, nil
+}
+
+func (l *wrapperLauncher) String() string {
+	return "wrapper " + l.text
+}
+
+func (l *wrapperLauncher) Command(t TargetT) ([]string, []string, error) {
+	var buf bytes.Buffer
+	if err := l.template.Execute(&buf, t); err != nil {
+		return nil, nil, fmt.Errorf("Cannot execute template of launcher wrapper: %v", err)
+	}
+	return shellArgs(buf.String()), openMPEnv(t), nil
+}
+
+func init() {
+	Register("wrapper", newWrapper)
+}
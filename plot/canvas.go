@@ -0,0 +1,176 @@
+package plot
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// colors of the series, the first two match the gnuplot line styles
+var palette = []string{"#0060ad", "#dd181f", "#2ca02c", "#ff7f0e", "#9467bd", "#8c564b"}
+
+const (
+	marginLeft   = 70
+	marginRight  = 40
+	marginTop    = 30
+	marginBottom = 50
+
+	// size of a single chart in figures
+	chartWidth  = 640
+	chartHeight = 360
+)
+
+type textStyleT struct {
+	size     float64
+	bold     bool
+	vertical bool
+}
+
+// canvasT is drawn on by the native backends. Colors are #rrggbb.
+type canvasT interface {
+	line(x1, y1, x2, y2 float64, color string, width float64, dashed bool)
+	path(xs, ys []float64, color string, width float64, dashed bool)
+	circle(x, y, r float64, color string, title string)
+	rect(x, y, w, h float64, fill string)
+	// anchor is start, middle or end
+	text(x, y float64, anchor string, s string, style textStyleT)
+}
+
+var normalText = textStyleT{size: 11}
+
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
+
+// draws the chart into the rectangle starting at x0, y0
+func drawChart(cv canvasT, c *ChartT, x0, y0, width, height float64) {
+	xmin, xmax, ymin, ymax := c.bounds()
+	xticks := ticks(xmin, xmax, 6)
+	yticks := ticks(ymin, ymax, 5)
+	xmin, xmax = math.Min(xmin, xticks[0]), math.Max(xmax, xticks[len(xticks)-1])
+	ymin, ymax = math.Min(ymin, yticks[0]), math.Max(ymax, yticks[len(yticks)-1])
+
+	w := width - marginLeft - marginRight
+	h := height - marginTop - marginBottom
+	px := func(x float64) float64 { return x0 + marginLeft + (x-xmin)/(xmax-xmin)*w }
+	py := func(y float64) float64 { return y0 + marginTop + h - (y-ymin)/(ymax-ymin)*h }
+
+	cv.text(x0+width/2, y0+18, "middle", c.Title, textStyleT{size: 14})
+
+	// axes and tics
+	cv.path([]float64{px(xmin), px(xmin), px(xmax)}, []float64{py(ymax), py(ymin), py(ymin)}, "#000000", 1, false)
+	labelled := make(map[float64]bool)
+	for _, t := range c.XTics {
+		labelled[t.Pos] = true
+	}
+	for _, x := range xticks {
+		if labelled[x] {
+			continue
+		}
+		cv.line(px(x), py(ymin), px(x), py(ymin)+4, "#000000", 1, false)
+		cv.text(px(x), py(ymin)+16, "middle", formatNumber(x), normalText)
+	}
+	for _, t := range c.XTics {
+		cv.line(px(t.Pos), py(ymin), px(t.Pos), py(ymin)+4, "#000000", 1, false)
+		cv.text(px(t.Pos), py(ymin)+16, "middle", t.Label, textStyleT{size: 11, bold: true})
+	}
+	for _, y := range yticks {
+		cv.line(px(xmin), py(y), px(xmax), py(y), "#dddddd", 1, false)
+		cv.text(px(xmin)-6, py(y)+4, "end", formatNumber(y), normalText)
+	}
+	for _, m := range c.Markers {
+		cv.line(px(m), py(ymin), px(m), py(ymax), "#808080", 1, true)
+	}
+	cv.text(x0+marginLeft+w/2, y0+height-10, "middle", c.XLabel, normalText)
+	cv.text(x0+14, y0+marginTop+h/2, "middle", c.YLabel, textStyleT{size: 11, vertical: true})
+
+	// data
+	for i, s := range c.Series {
+		color := palette[i%len(palette)]
+		if s.Lines {
+			// NaN values interrupt the line
+			var xs, ys []float64
+			for _, p := range s.Points {
+				if math.IsNaN(p.X) || math.IsNaN(p.Y) {
+					if len(xs) > 1 {
+						cv.path(xs, ys, color, 2, s.Dashed)
+					}
+					xs, ys = nil, nil
+					continue
+				}
+				xs, ys = append(xs, px(p.X)), append(ys, py(p.Y))
+			}
+			if len(xs) > 1 {
+				cv.path(xs, ys, color, 2, s.Dashed)
+			}
+		}
+		for _, p := range s.Points {
+			if math.IsNaN(p.X) || math.IsNaN(p.Y) {
+				continue
+			}
+			if !math.IsNaN(p.Low) && !math.IsNaN(p.High) {
+				cv.line(px(p.X), py(p.Low), px(p.X), py(p.High), color, 1, false)
+				cv.line(px(p.X)-3, py(p.Low), px(p.X)+3, py(p.Low), color, 1, false)
+				cv.line(px(p.X)-3, py(p.High), px(p.X)+3, py(p.High), color, 1, false)
+			}
+			if !s.HidePoints {
+				cv.circle(px(p.X), py(p.Y), 3, color, formatNumber(p.X)+": "+formatNumber(p.Y))
+			}
+		}
+
+		// legend
+		ly := y0 + marginTop + float64(14*i) + 8
+		cv.line(px(xmax)-20, ly-4, px(xmax)-5, ly-4, color, 2, s.Dashed)
+		cv.text(px(xmax)-25, ly, "end", s.Name, normalText)
+	}
+}
+
+// color of v between min (white) and max (red)
+func heatColor(v float64, min float64, max float64) string {
+	t := 0.0
+	if max > min {
+		t = (v - min) / (max - min)
+	}
+	g := int(255 - t*(255-24))
+	return fmt.Sprintf("#%02x%02x%02x", 255-int(t*(255-221)), g, g)
+}
+
+// draws the heatmap into the rectangle starting at x0, y0
+func drawHeatmap(cv canvasT, hm *HeatmapT, x0, y0, width, height float64) {
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, row := range hm.Values {
+		for _, v := range row {
+			if !math.IsNaN(v) {
+				min, max = math.Min(min, v), math.Max(max, v)
+			}
+		}
+	}
+
+	const labelWidth = 120
+	const labelHeight = 60
+	cw := (width - labelWidth - marginRight) / float64(len(hm.Columns))
+	ch := (height - marginTop - labelHeight) / float64(len(hm.Rows))
+
+	cv.text(x0+width/2, y0+18, "middle", hm.Title, textStyleT{size: 14})
+
+	for j, col := range hm.Columns {
+		cv.text(x0+labelWidth+(float64(j)+0.5)*cw, y0+marginTop+labelHeight-6, "middle", col, normalText)
+	}
+	for i, row := range hm.Rows {
+		y := y0 + marginTop + labelHeight + float64(i)*ch
+		cv.text(x0+labelWidth-6, y+ch/2+4, "end", row, normalText)
+		for j := range hm.Columns {
+			x := x0 + labelWidth + float64(j)*cw
+			v := hm.Values[i][j]
+			color, text := "#dddddd", "-"
+			if !math.IsNaN(v) {
+				color, text = heatColor(v, min, max), fmt.Sprintf("%.3f", v)
+				if hm.Marked != nil && hm.Marked[i][j] {
+					text += "*"
+				}
+			}
+			cv.rect(x+1, y+1, cw-2, ch-2, color)
+			cv.text(x+cw/2, y+ch/2+4, "middle", text, normalText)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package plot
+
+import (
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	Register(gnuplotBackend{})
+}
+
+// characters with a special meaning in enhanced text mode
+var gnuplotEscaper = strings.NewReplacer(
+	"\\", "\\\\",
+	"_", "\\_",
+	"^", "\\^",
+	"{", "\\{",
+	"}", "\\}",
+	"@", "\\@",
+	"&", "\\&",
+	"~", "\\~",
+	"'", "''",
+)
+
+// GnuplotEscape escapes s to be used within a single quoted string in enhanced text mode
+func GnuplotEscape(s string) string {
+	return gnuplotEscaper.Replace(s)
+}
+
+func gnuplotFloat(v float64) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'E', -1, 64)
+}
+
+func gnuplotHeader(output string) string {
+	ret := "set output '" + output + "'\n"
+	ret += "set terminal pdf\n"
+	ret += "set key right bottom\n"
+	ret += "unset x2tics\n"
+	ret += "unset y2tics\n"
+	ret += "set border 3\n"
+	for i, c := range palette {
+		ret += "set style line " + strconv.Itoa(i+1) + " lc rgb '" + c + "' lt 1 lw 2 pt 7 ps 0.3\n"
+	}
+	ret += "set pointintervalbox 3\n"
+	return ret
+}
+
+// data of the series as inline data block
+func gnuplotDataBlock(name string, s *SeriesT) string {
+	ret := name + " << EOD\n"
+	for _, p := range s.Points {
+		ret += gnuplotFloat(p.X) + " " + gnuplotFloat(p.Y) + " " + gnuplotFloat(p.Low) + " " + gnuplotFloat(p.High) + "\n"
+	}
+	return ret + "EOD\n"
+}
+
+func hasErrorBars(s *SeriesT) bool {
+	for _, p := range s.Points {
+		if !math.IsNaN(p.Low) && !math.IsNaN(p.High) {
+			return true
+		}
+	}
+	return false
+}
+
+// gnuplot commands for a single chart, n is used to create unique data block names
+func gnuplotChart(c *ChartT, n int) string {
+	var ret string
+	var plots []string
+	for i := range c.Series {
+		s := &c.Series[i]
+		block := "$data" + strconv.Itoa(n) + "_" + strconv.Itoa(i)
+		ret += gnuplotDataBlock(block, s)
+
+		ls := " ls " + strconv.Itoa(i%len(palette)+1)
+		if hasErrorBars(s) {
+			plots = append(plots, block+" using 1:2:3:4 with yerrorbars"+ls+" title ''")
+		}
+		style := "points"
+		switch {
+		case s.Lines && s.HidePoints:
+			style = "lines"
+		case s.Lines:
+			style = "linespoints"
+		}
+		if s.Dashed {
+			ls += " dt 2"
+		}
+		plots = append(plots, block+" using 1:2 with "+style+ls+" title '"+GnuplotEscape(s.Name)+"'")
+	}
+
+	ret += "set title '" + GnuplotEscape(c.Title) + "'\n"
+	ret += "set xlabel '" + GnuplotEscape(c.XLabel) + "'\n"
+	ret += "set ylabel '" + GnuplotEscape(c.YLabel) + "'\n"
+	ret += "unset xtics\nset xtics\n"
+	for _, t := range c.XTics {
+		ret += "set xtics add ('" + GnuplotEscape(t.Label) + "' " + gnuplotFloat(t.Pos) + ")\n"
+	}
+	if c.YFromZero {
+		ret += "set yrange [0:*]\n"
+	} else {
+		ret += "set yrange [*:*]\n"
+	}
+	ret += "unset arrow\n"
+	for _, m := range c.Markers {
+		ret += "set arrow from " + gnuplotFloat(m) + ",graph 0 to " + gnuplotFloat(m) + ",graph 1 nohead dt 2\n"
+	}
+	if len(plots) == 0 {
+		return ret
+	}
+	return ret + "plot " + strings.Join(plots, ", ") + "\n"
+}
+
+// gnuplotBackend creates a gnuplot script with the data inlined, every chart is a page of the pdf created by it
+type gnuplotBackend struct{}
+
+func (gnuplotBackend) Name() string {
+	return "gnuplot"
+}
+
+func (gnuplotBackend) Write(f *FigureT) ([]string, error) {
+	ret := gnuplotHeader(f.Name + ".pdf")
+	for i := range f.Charts {
+		ret += gnuplotChart(&f.Charts[i], i)
+	}
+
+	filename := filepath.Join(f.Dir, f.Name+".plot")
+	return []string{filename}, ioutil.WriteFile(filename, []byte(ret), 0644)
+}
@@ -0,0 +1,177 @@
+// Package plot renders simple charts without external tools
+package plot
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// PointT is a single data point
+type PointT struct {
+	X float64
+	Y float64
+	// error bar, NaN if the point has none
+	Low  float64
+	High float64
+}
+
+// SeriesT is a named set of points
+type SeriesT struct {
+	Name   string
+	Points []PointT
+	// connect the points with lines
+	Lines bool
+	// only draw the lines, e.g. for fitted curves
+	HidePoints bool
+	Dashed     bool
+}
+
+// TicT is a labelled tic on an axis
+type TicT struct {
+	Pos   float64
+	Label string
+}
+
+// ChartT is a xy chart
+type ChartT struct {
+	Title  string
+	XLabel string
+	YLabel string
+	Series []SeriesT
+	// labelled x tics in addition to the numeric ones
+	XTics []TicT
+	// x positions marked with a vertical dashed line
+	Markers []float64
+	// y axis starts at 0
+	YFromZero bool
+}
+
+// FigureT is a set of charts stored together, e.g. as pages of one pdf
+type FigureT struct {
+	// base name of the created files
+	Name string
+	// directory of the created files, the current directory if empty
+	Dir    string
+	Charts []ChartT
+}
+
+// BackendT stores figures in one output format
+type BackendT interface {
+	Name() string
+	// Write creates the files of the figure in its directory and returns their names
+	Write(f *FigureT) ([]string, error)
+}
+
+var backends = make(map[string]BackendT)
+
+// Register makes a backend available by its name
+func Register(b BackendT) {
+	backends[b.Name()] = b
+}
+
+// Get returns the backend registered as name
+func Get(name string) (BackendT, error) {
+	b, exists := backends[name]
+	if !exists {
+		return nil, fmt.Errorf("Unknown plot backend %v", name)
+	}
+	return b, nil
+}
+
+// Backends returns the names of all registered backends
+func Backends() []string {
+	var ret []string
+	for name := range backends {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// HeatmapT is a matrix of values
+type HeatmapT struct {
+	Title   string
+	Rows    []string
+	Columns []string
+	// NaN for missing values
+	Values [][]float64
+	// marked values are printed with a trailing *, may be nil
+	Marked [][]bool
+}
+
+// Point creates a point without error bar
+func Point(x float64, y float64) PointT {
+	return PointT{X: x, Y: y, Low: math.NaN(), High: math.NaN()}
+}
+
+// data range of the chart, NaN values are ignored
+func (c *ChartT) bounds() (xmin, xmax, ymin, ymax float64) {
+	xmin, ymin = math.Inf(1), math.Inf(1)
+	xmax, ymax = math.Inf(-1), math.Inf(-1)
+
+	updateX := func(x float64) {
+		if !math.IsNaN(x) && !math.IsInf(x, 0) {
+			xmin, xmax = math.Min(xmin, x), math.Max(xmax, x)
+		}
+	}
+	updateY := func(y float64) {
+		if !math.IsNaN(y) && !math.IsInf(y, 0) {
+			ymin, ymax = math.Min(ymin, y), math.Max(ymax, y)
+		}
+	}
+
+	for _, s := range c.Series {
+		for _, p := range s.Points {
+			if math.IsNaN(p.Y) {
+				continue
+			}
+			updateX(p.X)
+			updateY(p.Y)
+			updateY(p.Low)
+			updateY(p.High)
+		}
+	}
+	for _, t := range c.XTics {
+		updateX(t.Pos)
+	}
+	if c.YFromZero {
+		updateY(0)
+	}
+
+	if math.IsInf(xmin, 1) {
+		xmin, xmax = 0, 1
+	}
+	if math.IsInf(ymin, 1) {
+		ymin, ymax = 0, 1
+	}
+	if xmin == xmax {
+		xmin, xmax = xmin-1, xmax+1
+	}
+	if ymin == ymax {
+		ymin, ymax = ymin-1, ymax+1
+	}
+	return
+}
+
+// ticks returns about n nicely rounded tic positions covering [min, max]. min < max is required.
+func ticks(min float64, max float64, n int) []float64 {
+	raw := (max - min) / float64(n)
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	step := magnitude
+	for _, f := range []float64{1, 2, 2.5, 5, 10} {
+		step = f * magnitude
+		if step >= raw {
+			break
+		}
+	}
+
+	// the first tic is <= min, the last >= max
+	var ret []float64
+	for i := math.Floor(min / step); ; i++ {
+		ret = append(ret, i*step)
+		if i*step >= max {
+			return ret
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package plot
+
+import (
+	"encoding/xml"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTicks(t *testing.T) {
+	expected := []struct {
+		min, max float64
+		n        int
+		ticks    []float64
+	}{
+		{0, 1.6, 5, []float64{0, 0.5, 1, 1.5, 2}},
+		{3, 27, 6, []float64{0, 5, 10, 15, 20, 25, 30}},
+		{-1, 1, 2, []float64{-1, 0, 1}},
+	}
+
+	for _, e := range expected {
+		if ticks := ticks(e.min, e.max, e.n); !reflect.DeepEqual(ticks, e.ticks) {
+			t.Errorf("ticks(%v, %v, %v) = %v, expected %v", e.min, e.max, e.n, ticks, e.ticks)
+		}
+	}
+}
+
+// parses the svg and returns all texts
+func svgTexts(t *testing.T, svg string) []string {
+	var ret []string
+	d := xml.NewDecoder(strings.NewReader(svg))
+	inText := false
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return ret
+		}
+		if err != nil {
+			t.Fatalf("Invalid svg: %v\n%v", err, svg)
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			inText = tok.Name.Local == "text"
+		case xml.CharData:
+			if inText {
+				ret = append(ret, string(tok))
+			}
+		case xml.EndElement:
+			inText = false
+		}
+	}
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestChartSVG(t *testing.T) {
+	c := ChartT{
+		Title:  "a < b",
+		XLabel: "x",
+		YLabel: "y",
+		Series: []SeriesT{
+			{Name: "s", Lines: true, Points: []PointT{{X: 1, Y: 1, Low: 0.5, High: 1.5}, Point(2, math.NaN()), Point(3, 2)}},
+		},
+		XTics:     []TicT{{Pos: 4, Label: "no CAT"}},
+		YFromZero: true,
+	}
+
+	texts := svgTexts(t, c.SVG(400, 300))
+	for _, s := range []string{"a < b", "no CAT", "s", "x", "y"} {
+		if !contains(texts, s) {
+			t.Errorf("Missing text %q in %v", s, texts)
+		}
+	}
+}
+
+func TestHeatmapSVG(t *testing.T) {
+	hm := HeatmapT{
+		Title:   "matrix",
+		Rows:    []string{"a", "b"},
+		Columns: []string{"a", "b"},
+		Values:  [][]float64{{math.NaN(), 1.5}, {1.25, math.NaN()}},
+		Marked:  [][]bool{{false, true}, {false, false}},
+	}
+
+	texts := svgTexts(t, hm.SVG(300, 300))
+	for _, s := range []string{"matrix", "1.500*", "1.250", "-"} {
+		if !contains(texts, s) {
+			t.Errorf("Missing text %q in %v", s, texts)
+		}
+	}
+}
+
+func TestGnuplotEscape(t *testing.T) {
+	expected := map[string]string{
+		"a_b":    "a\\_b",
+		"x^2":    "x\\^2",
+		"{a}":    "\\{a\\}",
+		"it's":   "it''s",
+		"a\\_b":  "a\\\\\\_b",
+		"no CAT": "no CAT",
+	}
+
+	for s, e := range expected {
+		if escaped := GnuplotEscape(s); escaped != e {
+			t.Errorf("GnuplotEscape(%q) = %q, expected %q", s, escaped, e)
+		}
+	}
+}
+
+func TestBackends(t *testing.T) {
+	dir, err := ioutil.TempDir("", "plot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fit := SeriesT{Name: "fit", Lines: true, HidePoints: true, Dashed: true, Points: []PointT{Point(1, 1), Point(3, 2)}}
+	c := ChartT{
+		Title:  "app_1",
+		Series: []SeriesT{{Name: "s", Lines: true, Points: []PointT{{X: 1, Y: 1, Low: 0.5, High: 1.5}, Point(3, 2)}}, fit},
+		XTics:  []TicT{{Pos: 4, Label: "no CAT"}},
+		// vertical dashed line
+		Markers: []float64{2},
+	}
+	f := FigureT{Name: "figure", Dir: dir, Charts: []ChartT{c, c}}
+
+	for _, name := range []string{"gnuplot", "png", "svg"} {
+		b, err := Get(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files, err := b.Write(&f)
+		if err != nil || len(files) != 1 {
+			t.Fatalf("%v: Write returned %v, %v", name, files, err)
+		}
+		content, err := ioutil.ReadFile(files[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		switch name {
+		case "gnuplot":
+			script := string(content)
+			for _, s := range []string{"set output '" + f.Name + ".pdf'", "set title 'app\\_1'", "$data1_1 << EOD", "with yerrorbars", "with lines ls 2 dt 2 title 'fit'", "set arrow from"} {
+				if !strings.Contains(script, s) {
+					t.Errorf("Missing %q in gnuplot script\n%v", s, script)
+				}
+			}
+		case "png":
+			img, err := png.Decode(strings.NewReader(string(content)))
+			if err != nil {
+				t.Fatalf("Invalid png: %v", err)
+			}
+			if size := img.Bounds().Size(); size.X != chartWidth || size.Y != 2*chartHeight {
+				t.Errorf("png has size %v, expected %vx%v", size, chartWidth, 2*chartHeight)
+			}
+		case "svg":
+			texts := svgTexts(t, string(content))
+			if !contains(texts, "app_1") {
+				t.Errorf("Missing title in %v", texts)
+			}
+		}
+	}
+
+	if _, err := Get("unknown"); err == nil {
+		t.Errorf("Get of an unknown backend succeeded")
+	}
+}
@@ -0,0 +1,167 @@
+package plot
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+func init() {
+	Register(pngBackend{})
+}
+
+// pngCanvas rasterizes on an RGBA image. Text always uses the fixed size basic font.
+type pngCanvas struct {
+	img *image.RGBA
+}
+
+func newPNGCanvas(width int, height int) *pngCanvas {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	return &pngCanvas{img: img}
+}
+
+// converts #rrggbb, invalid colors are black
+func parseColor(s string) color.RGBA {
+	if len(s) != 7 || s[0] != '#' {
+		return color.RGBA{A: 255}
+	}
+	v, err := strconv.ParseUint(s[1:], 16, 32)
+	if err != nil {
+		return color.RGBA{A: 255}
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 255}
+}
+
+// sets a square of size width around x, y
+func (cv *pngCanvas) stamp(x, y float64, c color.RGBA, width float64) {
+	n := int(math.Max(math.Round(width), 1))
+	cx, cy := int(math.Round(x))-(n-1)/2, int(math.Round(y))-(n-1)/2
+	for dy := 0; dy < n; dy++ {
+		for dx := 0; dx < n; dx++ {
+			cv.img.SetRGBA(cx+dx, cy+dy, c)
+		}
+	}
+}
+
+// offset continues the dash pattern from the previous segment of a path
+func (cv *pngCanvas) segment(x1, y1, x2, y2 float64, c color.RGBA, width float64, dashed bool, offset float64) float64 {
+	const dash, gap = 6, 3
+
+	length := math.Hypot(x2-x1, y2-y1)
+	steps := int(math.Ceil(length))
+	for i := 0; i <= steps; i++ {
+		t := 0.0
+		if steps > 0 {
+			t = float64(i) / float64(steps)
+		}
+		if dashed && math.Mod(offset+t*length, dash+gap) >= dash {
+			continue
+		}
+		cv.stamp(x1+t*(x2-x1), y1+t*(y2-y1), c, width)
+	}
+	return offset + length
+}
+
+func (cv *pngCanvas) line(x1, y1, x2, y2 float64, color string, width float64, dashed bool) {
+	cv.segment(x1, y1, x2, y2, parseColor(color), width, dashed, 0)
+}
+
+func (cv *pngCanvas) path(xs, ys []float64, color string, width float64, dashed bool) {
+	c := parseColor(color)
+	offset := 0.0
+	for i := 1; i < len(xs); i++ {
+		offset = cv.segment(xs[i-1], ys[i-1], xs[i], ys[i], c, width, dashed, offset)
+	}
+}
+
+func (cv *pngCanvas) circle(x, y, r float64, color string, title string) {
+	c := parseColor(color)
+	for dy := -r; dy <= r; dy++ {
+		for dx := -r; dx <= r; dx++ {
+			if dx*dx+dy*dy <= r*r {
+				cv.img.SetRGBA(int(math.Round(x+dx)), int(math.Round(y+dy)), c)
+			}
+		}
+	}
+}
+
+func (cv *pngCanvas) rect(x, y, w, h float64, fill string) {
+	r := image.Rect(int(math.Round(x)), int(math.Round(y)), int(math.Round(x+w)), int(math.Round(y+h)))
+	draw.Draw(cv.img, r, &image.Uniform{C: parseColor(fill)}, image.Point{}, draw.Src)
+}
+
+func (cv *pngCanvas) text(x, y float64, anchor string, s string, style textStyleT) {
+	face := basicfont.Face7x13
+	width := font.MeasureString(face, s).Ceil()
+	if style.bold {
+		width++
+	}
+	shift := 0
+	switch anchor {
+	case "middle":
+		shift = width / 2
+	case "end":
+		shift = width
+	}
+
+	// the text is rendered on a transparent image first as it may be rotated
+	height := face.Height
+	temp := image.NewRGBA(image.Rect(0, 0, width, height))
+	d := font.Drawer{Dst: temp, Src: image.Black, Face: face}
+	for i := 0; i == 0 || (style.bold && i < 2); i++ {
+		d.Dot = fixed.P(i, face.Ascent)
+		d.DrawString(s)
+	}
+
+	if !style.vertical {
+		pos := image.Pt(int(math.Round(x))-shift, int(math.Round(y))-face.Ascent)
+		draw.Draw(cv.img, temp.Bounds().Add(pos), temp, image.Point{}, draw.Over)
+		return
+	}
+
+	// rotated by 90 degrees counterclockwise around x, y
+	for ty := 0; ty < height; ty++ {
+		for tx := 0; tx < width; tx++ {
+			c := temp.RGBAAt(tx, ty)
+			if c.A == 0 {
+				continue
+			}
+			px := int(math.Round(x)) + ty - face.Ascent
+			py := int(math.Round(y)) - tx + shift
+			cv.img.Set(px, py, color.NRGBA{A: c.A})
+		}
+	}
+}
+
+// pngBackend stores all charts of a figure below each other in one png file
+type pngBackend struct{}
+
+func (pngBackend) Name() string {
+	return "png"
+}
+
+func (pngBackend) Write(f *FigureT) ([]string, error) {
+	cv := newPNGCanvas(chartWidth, chartHeight*len(f.Charts))
+	for i := range f.Charts {
+		drawChart(cv, &f.Charts[i], 0, float64(chartHeight*i), chartWidth, chartHeight)
+	}
+
+	filename := filepath.Join(f.Dir, f.Name+".png")
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return []string{filename}, png.Encode(file, cv.img)
+}
@@ -0,0 +1,104 @@
+package plot
+
+import (
+	"fmt"
+	"html"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+func init() {
+	Register(svgBackend{})
+}
+
+type svgCanvas struct {
+	b strings.Builder
+}
+
+func newSVGCanvas(width int, height int) *svgCanvas {
+	var cv svgCanvas
+	fmt.Fprintf(&cv.b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" font-family=\"sans-serif\" font-size=\"11\">\n", width, height)
+	return &cv
+}
+
+func (cv *svgCanvas) String() string {
+	return cv.b.String() + "</svg>\n"
+}
+
+func svgStroke(color string, width float64, dashed bool) string {
+	ret := fmt.Sprintf("stroke=\"%v\" stroke-width=\"%v\"", color, width)
+	if dashed {
+		ret += " stroke-dasharray=\"6 3\""
+	}
+	return ret
+}
+
+func (cv *svgCanvas) line(x1, y1, x2, y2 float64, color string, width float64, dashed bool) {
+	fmt.Fprintf(&cv.b, "<line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" %v/>\n", x1, y1, x2, y2, svgStroke(color, width, dashed))
+}
+
+func (cv *svgCanvas) path(xs, ys []float64, color string, width float64, dashed bool) {
+	var d []string
+	for i := range xs {
+		cmd := "L"
+		if i == 0 {
+			cmd = "M"
+		}
+		d = append(d, fmt.Sprintf("%v%.1f %.1f", cmd, xs[i], ys[i]))
+	}
+	fmt.Fprintf(&cv.b, "<path d=\"%v\" %v fill=\"none\"/>\n", strings.Join(d, " "), svgStroke(color, width, dashed))
+}
+
+func (cv *svgCanvas) circle(x, y, r float64, color string, title string) {
+	fmt.Fprintf(&cv.b, "<circle cx=\"%.1f\" cy=\"%.1f\" r=\"%v\" fill=\"%v\"><title>%v</title></circle>\n", x, y, r, color, html.EscapeString(title))
+}
+
+func (cv *svgCanvas) rect(x, y, w, h float64, fill string) {
+	fmt.Fprintf(&cv.b, "<rect x=\"%.1f\" y=\"%.1f\" width=\"%.1f\" height=\"%.1f\" fill=\"%v\"/>\n", x, y, w, h, fill)
+}
+
+func (cv *svgCanvas) text(x, y float64, anchor string, s string, style textStyleT) {
+	extra := ""
+	if style.size != normalText.size {
+		extra += fmt.Sprintf(" font-size=\"%v\"", style.size)
+	}
+	if style.bold {
+		extra += " font-weight=\"bold\""
+	}
+	if style.vertical {
+		extra += fmt.Sprintf(" transform=\"rotate(-90 %.1f %.1f)\"", x, y)
+	}
+	fmt.Fprintf(&cv.b, "<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"%v\"%v>%v</text>\n", x, y, anchor, extra, html.EscapeString(s))
+}
+
+// SVG renders the chart as a standalone svg element
+func (c *ChartT) SVG(width int, height int) string {
+	cv := newSVGCanvas(width, height)
+	drawChart(cv, c, 0, 0, float64(width), float64(height))
+	return cv.String()
+}
+
+// SVG renders the heatmap as a standalone svg element
+func (hm *HeatmapT) SVG(width int, height int) string {
+	cv := newSVGCanvas(width, height)
+	drawHeatmap(cv, hm, 0, 0, float64(width), float64(height))
+	return cv.String()
+}
+
+// svgBackend stores all charts of a figure below each other in one svg file
+type svgBackend struct{}
+
+func (svgBackend) Name() string {
+	return "svg"
+}
+
+func (svgBackend) Write(f *FigureT) ([]string, error) {
+	cv := newSVGCanvas(chartWidth, chartHeight*len(f.Charts))
+	for i := range f.Charts {
+		drawChart(cv, &f.Charts[i], 0, float64(chartHeight*i), chartWidth, chartHeight)
+	}
+
+	filename := filepath.Join(f.Dir, f.Name+".svg")
+	return []string{filename}, ioutil.WriteFile(filename, []byte(cv.String()), 0644)
+}
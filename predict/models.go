@@ -0,0 +1,40 @@
+package predict
+
+import (
+	"fmt"
+	"math"
+)
+
+func init() {
+	Register(halfCacheModel{})
+	Register(proportionalModel{})
+}
+
+// halfCacheModel assumes both applications share the cache equally
+type halfCacheModel struct{}
+
+func (halfCacheModel) Name() string {
+	return "half"
+}
+
+func (halfCacheModel) Predict(app ProfileT, cosched ProfileT) (float64, error) {
+	return app.SlowdownAt(float64(app.CacheBits) / 2), nil
+}
+
+// proportionalModel assumes each application occupies a share of the cache proportional to its pressure
+type proportionalModel struct{}
+
+func (proportionalModel) Name() string {
+	return "proportional"
+}
+
+func (proportionalModel) Predict(app ProfileT, cosched ProfileT) (float64, error) {
+	if math.IsNaN(app.Pressure) || math.IsNaN(cosched.Pressure) {
+		return 0, fmt.Errorf("The proportional model requires a pressure profile")
+	}
+	share := 0.5
+	if sum := app.Pressure + cosched.Pressure; sum > 0 {
+		share = app.Pressure / sum
+	}
+	return app.SlowdownAt(share * float64(app.CacheBits)), nil
+}
@@ -0,0 +1,174 @@
+// Package predict estimates the slowdown of co-scheduled applications from measurements of the applications running alone
+package predict
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/jbreitbart/coBench/stats"
+	mstats "github.com/montanaflynn/stats"
+)
+
+// ProfileT is everything a model knows about an application measured alone
+type ProfileT struct {
+	App string
+	// slowdown compared to the reference runtime by number of CAT bits. Includes the whole cache with slowdown 1.
+	CATCurve map[int]float64
+	// number of CAT bits of the whole cache
+	CacheBits int
+	// pressure the application puts on the cache or memory bandwidth, e.g. LLC-load-misses/s. NaN if unknown.
+	Pressure float64
+}
+
+// ModelT predicts the slowdown of app co-scheduled with cosched
+type ModelT interface {
+	Name() string
+	Predict(app ProfileT, cosched ProfileT) (float64, error)
+}
+
+var models = make(map[string]ModelT)
+
+// Register makes a model available by its name
+func Register(m ModelT) {
+	models[m.Name()] = m
+}
+
+// Get returns the model registered as name or nil
+func Get(name string) ModelT {
+	return models[name]
+}
+
+// Models returns the names of all registered models
+func Models() []string {
+	var ret []string
+	for name := range models {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Profile creates the profile of app from its reference and CAT runtimes in data.
+// pressureCounter is the perf counter used as pressure, its rate per second is used. Empty means no pressure.
+func Profile(data *stats.StatsT, app string, pressureCounter string, metric string) (ProfileT, error) {
+	ret := ProfileT{App: app, Pressure: math.NaN()}
+
+	ref := data.ReferenceRuntime(app)
+	cat := data.CATRuntimes(app)
+	if ref == nil || cat == nil || len(*cat) == 0 {
+		return ret, fmt.Errorf("No CAT measurements of %v", app)
+	}
+
+	keys := stats.SortedCATKeys(cat)
+	ret.CacheBits = data.WholeCacheBits(cat)
+
+	ret.CATCurve = make(map[int]float64)
+	for _, k := range keys {
+		r := (*cat)[k]
+		ret.CATCurve[k] = data.Slowdown(&r, ref, metric)
+	}
+	ret.CATCurve[ret.CacheBits] = 1
+
+	if pressureCounter != "" {
+		var rates []float64
+		for _, runs := range *ref.RawRuntimesByMask {
+			for _, run := range runs {
+				if run.Excluded() {
+					continue
+				}
+				if v, exists := stats.ParsePerfCounters(run.Output)[pressureCounter]; exists {
+					rates = append(rates, v/run.Runtime.Seconds())
+				}
+			}
+		}
+		if len(rates) == 0 {
+			return ret, fmt.Errorf("No perf counter %v in the reference runs of %v", pressureCounter, app)
+		}
+		ret.Pressure, _ = mstats.Mean(rates)
+	}
+
+	return ret, nil
+}
+
+// SlowdownAt interpolates the CAT curve linearly at bits
+func (p *ProfileT) SlowdownAt(bits float64) float64 {
+	var keys []int
+	for k := range p.CATCurve {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	if bits <= float64(keys[0]) {
+		return p.CATCurve[keys[0]]
+	}
+	for i := 1; i < len(keys); i++ {
+		if bits <= float64(keys[i]) {
+			lo, hi := float64(keys[i-1]), float64(keys[i])
+			f := (bits - lo) / (hi - lo)
+			return p.CATCurve[keys[i-1]]*(1-f) + p.CATCurve[keys[i]]*f
+		}
+	}
+	return p.CATCurve[keys[len(keys)-1]]
+}
+
+// ValidationT compares a prediction with the measured slowdown
+type ValidationT struct {
+	App       string
+	CoSched   string
+	Predicted float64
+	// NaN if the pair was not measured
+	Measured float64
+	// (Predicted - Measured) / Measured
+	RelError float64
+}
+
+// PredictAll predicts the slowdown of every ordered pair of apps and compares it with the measurements in data if available
+func PredictAll(data *stats.StatsT, model ModelT, apps []string, pressureCounter string, metric string) ([]ValidationT, error) {
+	profiles := make(map[string]ProfileT)
+	for _, app := range apps {
+		p, err := Profile(data, app, pressureCounter, metric)
+		if err != nil {
+			return nil, err
+		}
+		profiles[app] = p
+	}
+
+	var ret []ValidationT
+	for _, app := range apps {
+		for _, cosched := range apps {
+			if app == cosched {
+				continue
+			}
+			predicted, err := model.Predict(profiles[app], profiles[cosched])
+			if err != nil {
+				return nil, err
+			}
+
+			v := ValidationT{App: app, CoSched: cosched, Predicted: predicted, Measured: math.NaN(), RelError: math.NaN()}
+			if co := data.CoSchedRuntimes(app, cosched); co != nil {
+				v.Measured = data.Slowdown(co, data.ReferenceRuntime(app), metric)
+				v.RelError = (v.Predicted - v.Measured) / v.Measured
+			}
+			ret = append(ret, v)
+		}
+	}
+
+	return ret, nil
+}
+
+// MeanAbsRelError returns the mean absolute relative error of all validations with measurements
+func MeanAbsRelError(vs []ValidationT) float64 {
+	sum, n := 0.0, 0.0
+	for _, v := range vs {
+		if math.IsNaN(v.RelError) {
+			continue
+		}
+		sum += math.Abs(v.RelError)
+		n++
+	}
+	if n == 0 {
+		return math.NaN()
+	}
+	return sum / n
+}
@@ -0,0 +1,72 @@
+package predict
+
+import (
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jbreitbart/coBench/stats"
+)
+
+func runs(seconds float64, misses float64) []stats.DataPerRun {
+	r := make([]stats.DataPerRun, 2)
+	for i := range r {
+		r[i].Runtime = time.Duration(seconds * float64(time.Second))
+		r[i].Output = " Performance counter stats for 'app':\n\n   " + strconv.FormatFloat(misses, 'f', -1, 64) + "      LLC-load-misses\n\n"
+	}
+	return r
+}
+
+func TestPredict(t *testing.T) {
+	var data stats.StatsT
+	// 8 bits in total, individual CAT runs with 2, 4 and 6 bits
+	data.AddReferenceRuntime("p0", runs(1, 3))
+	data.AddCATRuntime("p0", 0x03, runs(2, 0))
+	data.AddCATRuntime("p0", 0x0f, runs(1.5, 0))
+	data.AddCATRuntime("p0", 0x3f, runs(1.2, 0))
+	data.AddReferenceRuntime("p1", runs(2, 2))
+	data.AddCATRuntime("p1", 0x03, runs(2, 0))
+	data.AddCATRuntime("p1", 0x0f, runs(2, 0))
+	data.AddCATRuntime("p1", 0x3f, runs(2, 0))
+	data.AddCoSchedRuntime("p0", "p1", runs(1.5, 0))
+
+	p, err := Profile(&data, "p0", "LLC-load-misses", stats.RuntimeMetric)
+	if err != nil {
+		t.Fatalf("Profile failed: %v", err)
+	}
+	if p.CacheBits != 8 || p.Pressure != 3 || p.CATCurve[8] != 1 {
+		t.Errorf("Unexpected profile %v", p)
+	}
+	if s := p.SlowdownAt(3); math.Abs(s-1.75) > 1e-9 {
+		t.Errorf("SlowdownAt(3) = %v, expected 1.75", s)
+	}
+
+	vs, err := PredictAll(&data, Get("half"), []string{"p0", "p1"}, "", stats.RuntimeMetric)
+	if err != nil || len(vs) != 2 {
+		t.Fatalf("PredictAll failed: %v %v", vs, err)
+	}
+	if vs[0].Predicted != 1.5 || vs[0].RelError != 0 || !math.IsNaN(vs[1].Measured) {
+		t.Errorf("Unexpected validation %v", vs)
+	}
+
+	// p0 gets 3/4 of the cache
+	vs, err = PredictAll(&data, Get("proportional"), []string{"p0", "p1"}, "LLC-load-misses", stats.RuntimeMetric)
+	if err != nil || math.Abs(vs[0].Predicted-1.2) > 1e-9 {
+		t.Errorf("Unexpected proportional prediction %v %v", vs, err)
+	}
+
+	if _, err = PredictAll(&data, Get("proportional"), []string{"p0", "p1"}, "", stats.RuntimeMetric); err == nil {
+		t.Errorf("The proportional model must fail without pressure")
+	}
+
+	// 11 bits in total measured with a step of 2 bits: 2, 4, 6 and 8 bits
+	data.AddReferenceRuntime("p2", runs(1, 0))
+	for _, mask := range []uint64{0x3, 0xf, 0x3f, 0xff} {
+		data.AddCATRuntime("p2", mask, runs(1, 0))
+	}
+	data.SetHardware(stats.HardwareT{CATBits: 11})
+	if p, err = Profile(&data, "p2", "", stats.RuntimeMetric); err != nil || p.CacheBits != 11 || p.CATCurve[11] != 1 {
+		t.Errorf("Unexpected profile with 11 CAT bits %v, %v", p, err)
+	}
+}
@@ -1,4 +1,4 @@
-package main
+package runner
 
 import (
 	"fmt"
@@ -12,12 +12,12 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-func generateCatConfigs(minBits uint64, numBits uint64) [][2]uint64 {
+func (r *RunnerT) generateCatConfigs(minBits uint64, numBits uint64) [][2]uint64 {
 	pairs := make([][2]uint64, 0)
 
-	if *cat {
-		for bits := minBits; bits <= numBits-minBits; bits += *catBitChunk {
-			if *inverseCat {
+	if r.opts.CAT {
+		for bits := minBits; bits <= numBits-minBits; bits += r.opts.CATChunk {
+			if r.opts.InverseCAT {
 				pairs = append(pairs, [2]uint64{bit.SetLastN(0, bits, numBits), bit.SetFirstN(0, bits)})
 			} else {
 				pairs = append(pairs, [2]uint64{bit.SetFirstN(0, bits), bit.SetLastN(0, bits, numBits)})
@@ -28,8 +28,13 @@ func generateCatConfigs(minBits uint64, numBits uint64) [][2]uint64 {
 	return pairs
 }
 
-func createDirsCAT() error {
-	for _, dir := range catDirs {
+// resctrl groups of the 1st and 2nd command
+func catDirs(resctrlPath string) []string {
+	return []string{resctrlPath + "/cobench0", resctrlPath + "/cobench1"}
+}
+
+func (r *RunnerT) createDirsCAT() error {
+	for _, dir := range catDirs(r.opts.ResctrlPath) {
 		err := os.Mkdir(dir, 0777)
 		if os.IsExist(err) {
 			continue
@@ -42,8 +47,8 @@ func createDirsCAT() error {
 	return nil
 }
 
-func removeDirsCAT() error {
-	for _, dir := range catDirs {
+func (r *RunnerT) removeDirsCAT() error {
+	for _, dir := range catDirs(r.opts.ResctrlPath) {
 		err := os.Remove(dir)
 		if err != nil {
 			return fmt.Errorf("Cannot remove dir %v: %v", dir, err)
@@ -53,12 +58,26 @@ func removeDirsCAT() error {
 	return nil
 }
 
-func resetCAT() error {
-	return removeDirsCAT()
+func (r *RunnerT) resetCAT() error {
+	return r.removeDirsCAT()
 }
 
-func readCATInfo() (minBits uint64, numBits uint64, err error) {
-	minBitsFilename := *resctrlPath + "/info/L3/min_cbm_bits"
+// ResetCAT removes the resctrl groups of coBench below resctrlPath, e.g. after an aborted benchmark. Missing groups are
+// ignored.
+func ResetCAT(resctrlPath string) error {
+	for _, dir := range catDirs(resctrlPath) {
+		err := os.Remove(dir)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("Cannot remove dir %v: %v", dir, err)
+		}
+	}
+
+	return nil
+}
+
+// ReadCATInfo returns the minimum number of bits of a capacity bitmask and the bits of the whole L3 cache
+func ReadCATInfo(resctrlPath string) (minBits uint64, numBits uint64, err error) {
+	minBitsFilename := resctrlPath + "/info/L3/min_cbm_bits"
 	minBitsByteTxt, err := ioutil.ReadFile(minBitsFilename)
 	if err != nil {
 		return
@@ -69,7 +88,7 @@ func readCATInfo() (minBits uint64, numBits uint64, err error) {
 		return
 	}
 
-	numBitsFilename := *resctrlPath + "/info/L3/cbm_mask"
+	numBitsFilename := resctrlPath + "/info/L3/cbm_mask"
 	numBitsByteTxt, err := ioutil.ReadFile(numBitsFilename)
 	if err != nil {
 		return
@@ -96,19 +115,16 @@ func readCATInfo() (minBits uint64, numBits uint64, err error) {
 	return
 }
 
-func setupCAT() (minBits uint64, numBits uint64, err error) {
+// creates the resctrl groups and assigns the CPUs of the 1st and 2nd command
+func (r *RunnerT) setupCAT() (err error) {
 
-	if minBits, numBits, err = readCATInfo(); err != nil {
-		return
-	}
-
-	if err = createDirsCAT(); err != nil {
+	if err = r.createDirsCAT(); err != nil {
 		return
 	}
 
 	numbers := regexp.MustCompile("[0-9]+")
 
-	for i, cpu := range cpus {
+	for i, cpu := range r.opts.CPUs {
 		cpuIDs := numbers.FindAllString(cpu, -1)
 		if len(cpuIDs)%2 != 0 {
 			err = fmt.Errorf("Unsupported CPU list: %v", cpu)
@@ -141,7 +157,7 @@ func setupCAT() (minBits uint64, numBits uint64, err error) {
 
 		var file *os.File
 
-		file, err = os.OpenFile(catDirs[i]+"/cpus", os.O_WRONLY|os.O_TRUNC, 0777)
+		file, err = os.OpenFile(catDirs(r.opts.ResctrlPath)[i]+"/cpus", os.O_WRONLY|os.O_TRUNC, 0777)
 		if err != nil {
 			err = fmt.Errorf("CAT could not open cpus file: %v", err)
 			return
@@ -158,13 +174,9 @@ func setupCAT() (minBits uint64, numBits uint64, err error) {
 	return
 }
 
-func writeCATConfig(configs [2]uint64) error {
-
-	if len(catDirs) != len(configs) {
-		return fmt.Errorf("Internal error")
-	}
+func (r *RunnerT) writeCATConfig(configs [2]uint64) error {
 
-	for i, dir := range catDirs {
+	for i, dir := range catDirs(r.opts.ResctrlPath) {
 		file, err := os.OpenFile(dir+"/schemata", os.O_WRONLY|os.O_TRUNC, 0777)
 		if err != nil {
 			return fmt.Errorf("CAT could not open cpus file: %v", err)
@@ -0,0 +1,90 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jbreitbart/coBench/cgroup"
+	"github.com/jbreitbart/coBench/stats"
+	log "github.com/sirupsen/logrus"
+)
+
+// cgroup of the application on the CPUs of cpuID, empty without cgroups. Every run creates its own group in it.
+func (r *RunnerT) cgroupSlot(cpuID int) string {
+	if r.opts.CgroupRoot == "" {
+		return ""
+	}
+	return cgroup.Dirs(r.opts.CgroupRoot)[cpuID]
+}
+
+// (re)creates the cgroups of the 1st and 2nd command on their CPUs with the limits
+func (r *RunnerT) setupCgroups(limits [2]stats.CgroupLimitsT) error {
+	return cgroup.Setup(r.opts.CgroupRoot, r.opts.CPUs, limits)
+}
+
+// restores the cgroups without limits after a sweep
+func (r *RunnerT) unlimitCgroups() {
+	if err := r.setupCgroups([2]stats.CgroupLimitsT{}); err != nil {
+		log.WithError(err).Errorln("Cannot remove the cgroup limits")
+	}
+}
+
+func (r *RunnerT) individualCgroupRuns(ctx context.Context, commands []string) error {
+	if len(r.opts.Cgroups) == 0 {
+		return nil
+	}
+	defer r.unlimitCgroups()
+
+	for _, c := range commands {
+
+		log.WithFields(log.Fields{
+			"app": c,
+		}).Infoln("Running app with cgroup limits")
+
+		for _, setting := range r.opts.Cgroups {
+			if err := r.setupCgroups(setting.Limits); err != nil {
+				return fmt.Errorf("Error setting cgroup limits %v: %v", setting.Name, err)
+			}
+
+			catConfig := [2]uint64{stats.NoCATMask, stats.NoCATMask}
+			runtime, err := r.runSingle(ctx, c, catConfig, setting.Name)
+			if err != nil {
+				return fmt.Errorf("Error running app %v: %v", c, err)
+			}
+
+			stat := r.stats.AddCgroupRuntime(c, setting.Name, runtime)
+			r.printStats(c, stat, catConfig[0], setting.Name)
+			r.progress([]string{c}, catConfig[:1], setting.Name, []stats.RuntimeT{stat})
+		}
+	}
+	return nil
+}
+
+func (r *RunnerT) coSchedCgroupRuns(ctx context.Context, commandPairs [][2]string) error {
+	if len(r.opts.Cgroups) == 0 {
+		return nil
+	}
+	defer r.unlimitCgroups()
+
+	for i, c := range commandPairs {
+		log.WithFields(log.Fields{
+			"app0": c[0],
+			"app1": c[1],
+		}).Infof("Running pair %v with cgroup limits", i)
+
+		for _, setting := range r.opts.Cgroups {
+			if err := r.setupCgroups(setting.Limits); err != nil {
+				return fmt.Errorf("Error setting cgroup limits %v: %v", setting.Name, err)
+			}
+
+			catConfig := [2]uint64{stats.NoCATMask, stats.NoCATMask}
+			runtimes, err := r.runPair(ctx, c, catConfig, setting.Name)
+			if err != nil {
+				return fmt.Errorf("Error running pair %v, %v: %v", c[0], c[1], err)
+			}
+
+			r.processRuntime(c, catConfig, setting.Name, runtimes)
+		}
+	}
+	return nil
+}
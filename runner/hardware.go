@@ -0,0 +1,166 @@
+package runner
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jbreitbart/coBench/stats"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	sysCPUPath    = "/sys/devices/system/cpu"
+	cpuInfoPath   = "/proc/cpuinfo"
+	osReleasePath = "/proc/sys/kernel/osrelease"
+)
+
+func readSysFile(filename string) (string, error) {
+	raw, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(raw)), nil
+}
+
+// parses cache sizes like 30720K
+func parseCacheSize(s string) (uint64, error) {
+	factor := uint64(1)
+	switch {
+	case strings.HasSuffix(s, "K"):
+		factor = 1024
+	case strings.HasSuffix(s, "M"):
+		factor = 1024 * 1024
+	}
+	size, err := strconv.ParseUint(strings.TrimRight(s, "KM"), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Unsupported cache size: %v", s)
+	}
+	return size * factor, nil
+}
+
+// reads the L3 cache of every CPU from sysfs
+func readL3Info(cpuPath string) (hw stats.HardwareT, err error) {
+	dirs, err := filepath.Glob(cpuPath + "/cpu[0-9]*/cache/index[0-9]*")
+	if err != nil {
+		return
+	}
+
+	// every L3 cache is shared by multiple CPUs and must only be counted once
+	caches := make(map[string]bool)
+
+	for _, dir := range dirs {
+		level, e := readSysFile(dir + "/level")
+		if e != nil || level != "3" {
+			continue
+		}
+
+		shared, e := readSysFile(dir + "/shared_cpu_list")
+		if e != nil {
+			err = e
+			return
+		}
+		caches[shared] = true
+
+		sizeTxt, e := readSysFile(dir + "/size")
+		if e != nil {
+			err = e
+			return
+		}
+		if hw.L3Size, err = parseCacheSize(sizeTxt); err != nil {
+			return
+		}
+
+		waysTxt, e := readSysFile(dir + "/ways_of_associativity")
+		if e != nil {
+			err = e
+			return
+		}
+		if hw.L3Ways, err = strconv.Atoi(waysTxt); err != nil {
+			return
+		}
+	}
+
+	if len(caches) == 0 {
+		err = fmt.Errorf("No L3 cache found in %v", cpuPath)
+		return
+	}
+	hw.L3Caches = len(caches)
+
+	return
+}
+
+// reads the model name of the 1st CPU from /proc/cpuinfo
+func readCPUModel(cpuInfo string) (string, error) {
+	file, err := os.Open(cpuInfo)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		kv := strings.SplitN(scanner.Text(), ":", 2)
+		if len(kv) == 2 && strings.TrimSpace(kv[0]) == "model name" {
+			return strings.TrimSpace(kv[1]), nil
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("No model name in %v", cpuInfo)
+}
+
+// DetectHardware reads the host name, the CPU model, the kernel release, the L3 cache and, if resctrlPath is not empty,
+// the CAT capacity bitmask. Missing information is only logged.
+func DetectHardware(resctrlPath string) stats.HardwareT {
+	hw, err := readL3Info(sysCPUPath)
+	if err != nil {
+		log.WithError(err).Warnln("Could not read L3 cache information")
+	}
+
+	if hw.Hostname, err = os.Hostname(); err != nil {
+		log.WithError(err).Warnln("Could not read the host name")
+	}
+	if hw.CPUModel, err = readCPUModel(cpuInfoPath); err != nil {
+		log.WithError(err).Warnln("Could not read the CPU model")
+	}
+	if hw.Kernel, err = readSysFile(osReleasePath); err != nil {
+		log.WithError(err).Warnln("Could not read the kernel release")
+	}
+
+	if resctrlPath != "" {
+		_, numBits, err := ReadCATInfo(resctrlPath)
+		if err != nil {
+			log.WithError(err).Warnln("Could not read CAT information")
+		}
+		hw.CATBits = int(numBits)
+	}
+
+	return hw
+}
+
+// stores the L3 cache and the CAT capacity bitmask in the result file
+func (r *RunnerT) storeHardware() {
+	resctrlPath := ""
+	if r.opts.CAT {
+		resctrlPath = r.opts.ResctrlPath
+	}
+	hw := DetectHardware(resctrlPath)
+
+	log.WithFields(log.Fields{
+		"host":            hw.Hostname,
+		"CPU":             hw.CPUModel,
+		"kernel":          hw.Kernel,
+		"L3 size (bytes)": hw.L3Size,
+		"L3 ways":         hw.L3Ways,
+		"L3 caches":       hw.L3Caches,
+		"CAT bits":        hw.CATBits,
+	}).Infoln("Hardware")
+
+	r.stats.SetHardware(hw)
+}
@@ -0,0 +1,177 @@
+package runner
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/jbreitbart/coBench/cgroup"
+	"github.com/jbreitbart/coBench/launcher"
+	"github.com/jbreitbart/coBench/stats"
+)
+
+// OptionsT configures a benchmark
+type OptionsT struct {
+	// minimum number of times the applications are executed
+	Runs int
+	// CPU lists of the 1st and 2nd command
+	CPUs    [2]string
+	Threads string
+	// launcher of the applications without an entry in Launchers, the shell launcher if nil
+	Launcher launcher.LauncherT
+	// launcher by command
+	Launchers   map[string]launcher.LauncherT
+	NoCoSched   bool
+	NoIndvSched bool
+
+	// root path of the resctrl file system
+	ResctrlPath string
+	// measure with all CAT settings
+	CAT        bool
+	InverseCAT bool
+	// bits changed from one CAT setting to the next
+	CATChunk uint64
+
+	// root of the cgroup v2 groups of the applications, cgroups are not used if empty
+	CgroupRoot string
+	// cgroup settings measured after the CAT settings, requires CgroupRoot
+	Cgroups []stats.CgroupSettingT
+
+	// stopping criterion, see stats.StatsT.SetStoppingCriterion
+	VarianceDiff float64
+	CIWidth      float64
+	CILevel      float64
+	MaxRuns      int
+	WarmupRuns   int
+
+	// perf stat events, perf stat is not used if empty
+	PerfStat     string
+	PerfInterval int
+
+	// outlier detection, see stats.StatsT.SetOutlierDetection. A NaN threshold selects the default of the method.
+	OutlierMethod    string
+	OutlierThreshold float64
+
+	// rules extracting the metrics reported by the applications from their output
+	MetricRules []stats.MetricRuleT
+	// metric used to compute the slowdown
+	SlowdownMetric string
+
+	// result file, the results are not stored if empty
+	Output string
+	// directory of the output logs of the applications, the working directory if empty
+	LogDir string
+
+	// called after every run of an application if not nil. Co-scheduled applications call it concurrently.
+	OnRun func(RunT)
+	// called after every measurement of an application or a pair with one CAT setting if not nil
+	OnProgress func(ProgressT)
+}
+
+// DefaultOptions returns the defaults of the coBench command line
+func DefaultOptions() OptionsT {
+	return OptionsT{
+		Runs:             2,
+		CPUs:             [2]string{"0-4", "5-9"},
+		Threads:          "5",
+		ResctrlPath:      "/sys/fs/resctrl/",
+		CATChunk:         2,
+		VarianceDiff:     math.NaN(),
+		CIWidth:          math.NaN(),
+		CILevel:          stats.DefaultCILevel,
+		OutlierMethod:    stats.NoOutlierDetection,
+		OutlierThreshold: math.NaN(),
+		SlowdownMetric:   stats.RuntimeMetric,
+	}
+}
+
+// Validate checks the options for invalid values and combinations
+func (o *OptionsT) Validate() error {
+	if o.Runs < 1 {
+		return fmt.Errorf("runs must be > 0")
+	}
+	if o.CATChunk < 1 {
+		return fmt.Errorf("catChunk must be > 0")
+	}
+	if o.MaxRuns != 0 && o.MaxRuns < o.Runs {
+		return fmt.Errorf("max-runs must be >= runs")
+	}
+	if o.WarmupRuns < 0 {
+		return fmt.Errorf("warmup must be >= 0")
+	}
+	if o.CILevel <= 0 || o.CILevel >= 1 {
+		return fmt.Errorf("ci-level must be in (0, 1)")
+	}
+	if !math.IsNaN(o.CIWidth) && o.CIWidth <= 0 {
+		return fmt.Errorf("ci must be > 0")
+	}
+	if !math.IsNaN(o.CIWidth) && !math.IsNaN(o.VarianceDiff) {
+		return fmt.Errorf("ci and variance cannot be combined")
+	}
+	if o.PerfInterval < 0 {
+		return fmt.Errorf("pstat-interval must be >= 0")
+	}
+	if o.PerfInterval > 0 && o.PerfStat == "" {
+		return fmt.Errorf("pstat-interval requires pstat")
+	}
+	if len(o.Cgroups) > 0 && o.CgroupRoot == "" {
+		return fmt.Errorf("cgroup-sweep requires cgroup-root")
+	}
+	if err := cgroup.Validate(o.Cgroups); err != nil {
+		return err
+	}
+	s, err := o.newStats()
+	if err != nil {
+		return err
+	}
+	if o.SlowdownMetric != stats.RuntimeMetric && s.MetricRule(o.SlowdownMetric) == nil {
+		return fmt.Errorf("slowdown-metric is not defined in the metric rules")
+	}
+	return nil
+}
+
+// empty dataset with the outlier detection and the metric rules of the options
+func (o *OptionsT) newStats() (*stats.StatsT, error) {
+	var s stats.StatsT
+	if err := s.SetOutlierDetection(o.OutlierMethod, o.OutlierThreshold); err != nil {
+		return nil, err
+	}
+	if err := s.SetMetricRules(o.MetricRules); err != nil {
+		return nil, fmt.Errorf("Invalid metric rules: %v", err)
+	}
+	return &s, nil
+}
+
+// launcher of the command c
+func (r *RunnerT) launcher(c string) launcher.LauncherT {
+	if l, exists := r.opts.Launchers[c]; exists {
+		return l
+	}
+	if r.opts.Launcher != nil {
+		return r.opts.Launcher
+	}
+	shell, _ := launcher.Parse("shell")
+	return shell
+}
+
+func (r *RunnerT) storeConfig(commands []string) {
+	defaultLauncher := r.launcher("").String()
+	hermitcore := strings.HasPrefix(defaultLauncher, "hermitcore")
+	if defaultLauncher == "shell" {
+		// result files without launchers used the shell
+		defaultLauncher = ""
+	}
+	var launchers map[string]string
+	for c, l := range r.opts.Launchers {
+		if launchers == nil {
+			launchers = make(map[string]string)
+		}
+		launchers[c] = l.String()
+	}
+
+	r.stats.SetCommandline(r.opts.CAT, r.opts.CATChunk, catDirs(r.opts.ResctrlPath), r.opts.CPUs, commands, hermitcore, r.opts.ResctrlPath, r.opts.Runs, r.opts.Threads, r.opts.VarianceDiff)
+	r.stats.SetLaunchers(defaultLauncher, launchers)
+	r.stats.SetCgroups(r.opts.CgroupRoot, r.opts.Cgroups)
+	r.stats.SetPerf(r.opts.PerfStat, r.opts.PerfInterval)
+	r.stats.SetStoppingCriterion(r.opts.CIWidth, r.opts.CILevel, r.opts.MaxRuns, r.opts.WarmupRuns)
+}
@@ -0,0 +1,350 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jbreitbart/coBench/cgroup"
+	"github.com/jbreitbart/coBench/commands"
+	"github.com/jbreitbart/coBench/launcher"
+	"github.com/jbreitbart/coBench/stats"
+	mstats "github.com/montanaflynn/stats"
+	log "github.com/sirupsen/logrus"
+)
+
+// command of an application and the launcher starting it
+type launchT struct {
+	cmd      *exec.Cmd
+	launcher launcher.LauncherT
+	target   launcher.TargetT
+}
+
+// starts cmd, a copy of l.cmd, for the target of the run and waits for it
+func (l *launchT) run(cmd *exec.Cmd, target launcher.TargetT) error {
+	var err error
+	if s, ok := l.launcher.(launcher.StarterT); ok {
+		err = s.Start(cmd, target)
+	} else {
+		err = cmd.Start()
+	}
+	if err != nil {
+		return err
+	}
+	return cmd.Wait()
+}
+
+// resctrl group of the application on the CPUs of cpuID, empty without CAT
+func (r *RunnerT) resctrlGroup(cpuID int, catConfig [2]uint64) string {
+	if catConfig[0] == 0 || catConfig[1] == 0 {
+		return ""
+	}
+	return catDirs(r.opts.ResctrlPath)[cpuID]
+}
+
+func (r *RunnerT) setupCmd(ctx context.Context, c string, cpuID int, catConfig [2]uint64, logFilename string) (*launchT, *os.File, error) {
+	l := &launchT{launcher: r.launcher(c)}
+
+	if r.opts.PerfStat != "" {
+		if r.opts.PerfInterval > 0 {
+			c = "perf stat -I " + strconv.Itoa(r.opts.PerfInterval) + " -e " + r.opts.PerfStat + " " + c
+		} else {
+			c = "perf stat -e " + r.opts.PerfStat + " " + c
+		}
+	}
+
+	l.target = launcher.TargetT{Command: c, CPUs: r.opts.CPUs[cpuID], Threads: r.opts.Threads, ResctrlGroup: r.resctrlGroup(cpuID, catConfig),
+		Cgroup: r.cgroupSlot(cpuID)}
+	args, env, err := l.launcher.Command(l.target)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, ok := l.launcher.(launcher.StarterT); !ok && l.target.Cgroup != "" {
+		args = cgroup.Command(args)
+	}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Env = append(os.Environ(), env...)
+	// the launched program and everything it starts are killed on cancellation
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	logFilename = filepath.Join(r.opts.LogDir, logFilename)
+
+	// try to avoid duplicate filenames; TODO not perfect
+	if _, err := os.Stat(logFilename + ".log"); err == nil {
+		for i := 0; i < 100; i++ {
+			if _, err := os.Stat(logFilename + "--" + strconv.Itoa(i) + ".log"); err != nil {
+				logFilename = logFilename + "-" + strconv.Itoa(i)
+				break
+			}
+		}
+	}
+	logFilename += ".log"
+
+	outfile, err := os.Create(logFilename)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error while creating file: %v", err)
+	}
+	cmd.Stdout = outfile
+	cmd.Stderr = outfile
+	l.cmd = cmd
+
+	return l, outfile, nil
+}
+
+func (r *RunnerT) runSingle(ctx context.Context, c string, catConfig [2]uint64, cgroupSetting string) ([]stats.DataPerRun, error) {
+
+	if catConfig[0] != 0 && catConfig[1] != 0 {
+		if err := r.writeCATConfig(catConfig); err != nil {
+			return nil, fmt.Errorf("Error while writting CAT config: %v", err)
+		}
+	}
+
+	filename := commands.Pretty(c)
+	if catConfig[0] != 0 && catConfig[1] != 0 {
+		filename += fmt.Sprintf("-%x", catConfig[0])
+	}
+	if cgroupSetting != "" {
+		filename += "-" + cgroupSetting
+	}
+	cmd, outFile, err := r.setupCmd(ctx, c, 0, catConfig, filename)
+	if err != nil {
+		return nil, err
+	}
+	defer outFile.Close()
+
+	// used to count how many apps have reached their min limit
+	done := make(chan int, 1)
+	done <- 1
+
+	// used to return an error from the go-routines
+	errs := make(chan error, 1)
+
+	// used to return the app runtimes
+	var runtimes []stats.DataPerRun
+
+	// used to wait for the following 2 goroutines
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go r.runCmdMinTimes(ctx, cmd, RunT{App: c, CATMask: catConfig[0], Cgroup: cgroupSetting}, &wg, &runtimes, done, errs)
+
+	wg.Wait()
+
+	if len(errs) != 0 {
+		return nil, <-errs
+	}
+
+	return runtimes, nil
+}
+
+func (r *RunnerT) runPair(ctx context.Context, cPair [2]string, catConfig [2]uint64, cgroupSetting string) ([][]stats.DataPerRun, error) {
+
+	if catConfig[0] != 0 && catConfig[1] != 0 {
+		if err := r.writeCATConfig(catConfig); err != nil {
+			return nil, fmt.Errorf("Error while writting CAT config: %v", err)
+		}
+	}
+
+	var cmds [len(r.opts.CPUs)]*launchT
+	// setup commands
+	for i := range cmds {
+		filename := fmt.Sprintf("%v-%v", commands.Pretty(cPair[i]), commands.Pretty(cPair[(i+1)%2]))
+		if catConfig[0] != 0 && catConfig[1] != 0 {
+			filename += fmt.Sprintf("-%x", catConfig[0])
+		}
+		if cgroupSetting != "" {
+			filename += "-" + cgroupSetting
+		}
+
+		var outFile *os.File
+		var err error
+		cmds[i], outFile, err = r.setupCmd(ctx, cPair[i], i, catConfig, filename)
+		if err != nil {
+			return nil, err
+		}
+		defer outFile.Close()
+	}
+
+	// used to count how many apps have reached their min limit
+	done := make(chan int, 1)
+	done <- 0
+
+	// used to return an error from the go-routines
+	errs := make(chan error, len(cmds))
+
+	// used to return the app runtimes
+	runtimes := make([][]stats.DataPerRun, len(cmds))
+
+	// used to wait for the following 2 goroutines
+	var wg sync.WaitGroup
+	wg.Add(len(cmds))
+
+	for i, c := range cmds {
+		run := RunT{App: cPair[i], CoSched: cPair[(i+1)%2], CATMask: catConfig[i], Cgroup: cgroupSetting}
+		go r.runCmdMinTimes(ctx, c, run, &wg, &runtimes[i], done, errs)
+	}
+
+	wg.Wait()
+
+	if len(errs) != 0 {
+		return nil, <-errs
+	}
+
+	return runtimes, nil
+}
+
+// executes the command of l at least r.opts.Runs times and until the other application is done as well. Every stored run is passed
+// to r.opts.OnRun with the app, co-scheduled app, CAT mask and cgroup setting of run. With cgroups every run is executed in
+// its own group in the cgroup of l.
+func (r *RunnerT) runCmdMinTimes(ctx context.Context, l *launchT, run RunT, wg *sync.WaitGroup, runtime *[]stats.DataPerRun, done chan int, errs chan error) {
+	defer wg.Done()
+
+	oldVariance := 0.0
+	var runtimeInSeconds []float64
+	completed := false
+
+	// i < 1 are warm-up runs
+	for i := 1 - r.opts.WarmupRuns; ; i++ {
+		if ctx.Err() != nil {
+			errs <- ctx.Err()
+			return
+		}
+
+		// create a copy of the command
+		cmd := *l.cmd
+		cmd.Cancel = func() error {
+			return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		}
+
+		var buf bytes.Buffer
+		cmd.Stdout = io.MultiWriter(cmd.Stdout, &buf)
+		cmd.Stderr = io.MultiWriter(cmd.Stderr, &buf)
+
+		// the statistics of the cgroup of the run describe only this run
+		target := l.target
+		if l.target.Cgroup != "" {
+			var err error
+			if target.Cgroup, err = cgroup.NewRun(l.target.Cgroup); err != nil {
+				errs <- err
+				return
+			}
+			cmd.Env = append(append([]string{}, l.cmd.Env...), cgroup.EnvVar+"="+target.Cgroup)
+		}
+
+		start := time.Now()
+		err := l.run(&cmd, target)
+
+		var data stats.DataPerRun
+		data.Runtime = time.Since(start)
+		data.Output = buf.String()
+		data.Start = start
+		// the rusage of a starter, e.g. a container runtime CLI, does not contain the application
+		if _, ok := l.launcher.(launcher.StarterT); !ok {
+			data.Rusage = rusage(cmd.ProcessState)
+		}
+		if r.opts.PerfInterval > 0 {
+			data.PerfSeries = stats.ParsePerfIntervals(data.Output, r.opts.PerfStat)
+			if data.PerfSeries == nil && err == nil {
+				r.perfSeriesWarning.Do(func() {
+					log.WithFields(log.Fields{"app": run.App, "events": r.opts.PerfStat}).Warnln("No perf stat intervals found in the output of the run")
+				})
+			}
+		}
+		if l.target.Cgroup != "" {
+			var statErr error
+			data.Cgroup, statErr = cgroup.ReadStats(target.Cgroup)
+			if e := cgroup.RemoveRun(target.Cgroup); e != nil {
+				log.WithError(e).WithField("cgroup", target.Cgroup).Warnln("Cannot remove the cgroup of the run")
+			}
+			if err == nil {
+				err = statErr
+			}
+		}
+
+		if ctx.Err() != nil {
+			errs <- ctx.Err()
+			return
+		}
+		if err != nil {
+			errs <- fmt.Errorf("Error running %v: %v", cmd.Args, err)
+			return
+		}
+
+		// did the other cmd result in an error?
+		if len(errs) != 0 {
+			return
+		}
+
+		d := <-done
+
+		// check if the other application was running the whole time
+		if d != len(r.opts.CPUs) {
+			// yes
+			data.Warmup = i < 1
+			*runtime = append(*runtime, data)
+			if !data.Warmup {
+				runtimeInSeconds = append(runtimeInSeconds, data.Runtime.Seconds())
+			}
+			if r.opts.OnRun != nil {
+				run.Data = data
+				r.opts.OnRun(run)
+			}
+		}
+
+		// did we run min times?
+		if !completed && i >= r.opts.Runs {
+			if r.precise(runtimeInSeconds, &oldVariance) || (r.opts.MaxRuns > 0 && i >= r.opts.MaxRuns) {
+				d++
+				completed = true
+			}
+		}
+		done <- d
+
+		// both applications are done
+		if d == len(r.opts.CPUs) {
+			return
+		}
+	}
+}
+
+// resource usage of the finished process, nil if unknown
+func rusage(state *os.ProcessState) *stats.RusageT {
+	if state == nil {
+		return nil
+	}
+	ru, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok {
+		return nil
+	}
+	return &stats.RusageT{
+		UserTime:                   time.Duration(ru.Utime.Nano()),
+		SystemTime:                 time.Duration(ru.Stime.Nano()),
+		MaxRSS:                     ru.Maxrss,
+		MinorFaults:                ru.Minflt,
+		MajorFaults:                ru.Majflt,
+		VoluntaryContextSwitches:   ru.Nvcsw,
+		InvoluntaryContextSwitches: ru.Nivcsw,
+	}
+}
+
+// checks if the runtimes are precise enough to stop executing the application
+func (r *RunnerT) precise(runtimeInSeconds []float64, oldVariance *float64) bool {
+	if !math.IsNaN(r.opts.CIWidth) {
+		_, rel, ok := stats.ConfidenceInterval(runtimeInSeconds, r.opts.CILevel)
+		return ok && rel <= r.opts.CIWidth
+	}
+
+	vari, _ := mstats.Variance(runtimeInSeconds)
+	ret := math.IsNaN(r.opts.VarianceDiff) || math.Abs(vari-*oldVariance) > r.opts.VarianceDiff
+	*oldVariance = vari
+	return ret
+}
@@ -0,0 +1,351 @@
+// Package runner executes the benchmarks of coBench: every application individually and every pair of applications
+// co-scheduled, optionally with all CAT settings and a sweep of cgroup settings. Every benchmark stores its measurements
+// in a new dataset of the runner.
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/jbreitbart/coBench/cgroup"
+	"github.com/jbreitbart/coBench/commands"
+	"github.com/jbreitbart/coBench/stats"
+	log "github.com/sirupsen/logrus"
+)
+
+// RunT is a single execution of an application as passed to OptionsT.OnRun
+type RunT struct {
+	App string
+	// co-scheduled application, empty for individual runs
+	CoSched string
+	// CAT mask of the application, stats.NoCATMask without CAT
+	CATMask uint64
+	// cgroup setting, empty without cgroup limits
+	Cgroup string
+	Data   stats.DataPerRun
+}
+
+// ProgressT describes a finished measurement of an application or a pair of applications with one CAT or cgroup setting
+// as passed to OptionsT.OnProgress
+type ProgressT struct {
+	// number of finished measurements including this one
+	Done int
+	// number of measurements of the benchmark
+	Total int
+	// applications of the measurement, one for individual runs
+	Apps []string
+	// CAT masks of the applications, stats.NoCATMask without CAT
+	CATMasks []uint64
+	// cgroup setting, empty without cgroup limits
+	Cgroup string
+	// statistics of the applications
+	Stats []stats.RuntimeT
+}
+
+// RunnerT executes a benchmark. A RunnerT must not be used by multiple goroutines at the same time.
+type RunnerT struct {
+	opts OptionsT
+
+	// measurements of the last benchmark
+	stats *stats.StatsT
+
+	// CAT settings of the pairs measured with CAT
+	catPairs [][2]uint64
+
+	// warns once per benchmark about runs without perf intervals
+	perfSeriesWarning sync.Once
+
+	done  int
+	total int
+}
+
+// New returns a runner with the validated options
+func New(o OptionsT) (*RunnerT, error) {
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	return &RunnerT{opts: o}, nil
+}
+
+// Stats returns the measurements of the last benchmark, nil before the first one
+func (r *RunnerT) Stats() *stats.StatsT {
+	return r.stats
+}
+
+// Run executes the benchmark of the commands and stores the measurements in a new dataset returned by Stats. If
+// o.Output is not empty the results are stored in this file, also if the benchmark failed or ctx was cancelled.
+func (r *RunnerT) Run(ctx context.Context, commandStrings []string) (err error) {
+	if len(commandStrings) < 1 || (len(commandStrings) < 2 && !r.opts.NoCoSched) {
+		return fmt.Errorf("You must provide more commands")
+	}
+
+	if r.stats, err = r.opts.newStats(); err != nil {
+		return err
+	}
+
+	r.catPairs = nil
+	r.perfSeriesWarning = sync.Once{}
+	if r.opts.CAT {
+		minBits, numBits, err := ReadCATInfo(r.opts.ResctrlPath)
+		if err != nil {
+			return fmt.Errorf("Error reading CAT information: %v", err)
+		}
+		r.catPairs = r.generateCatConfigs(minBits, numBits)
+	}
+
+	hostname, _ := os.Hostname()
+	log.WithField("host", hostname).Infoln("Benchmark started")
+
+	r.storeConfig(commandStrings)
+	r.storeHardware()
+
+	defer func() {
+		if e := r.cleanup(); err == nil {
+			err = e
+		}
+	}()
+
+	if r.opts.CgroupRoot != "" {
+		if err = r.setupCgroups([2]stats.CgroupLimitsT{}); err != nil {
+			return fmt.Errorf("Error setting up cgroups: %v", err)
+		}
+		defer cgroup.Reset(r.opts.CgroupRoot)
+	}
+
+	indvCommands := commands.GenerateIndv(commandStrings)
+
+	if len(indvCommands) != len(commandStrings) {
+		log.Infof("Remove %v duplicates from commands for individual runs.\n", len(commandStrings)-len(indvCommands))
+	}
+
+	commandPairs := commands.GeneratePairs(commandStrings)
+
+	r.done = 0
+	r.total = len(commandStrings)
+	if r.opts.CAT && !r.opts.NoIndvSched {
+		r.total += len(commandStrings) * len(r.catPairs)
+	}
+	if !r.opts.NoIndvSched {
+		r.total += len(commandStrings) * len(r.opts.Cgroups)
+	}
+	if !r.opts.NoCoSched {
+		r.total += len(commandPairs) * (1 + len(r.catPairs) + len(r.opts.Cgroups))
+	}
+
+	// run apps individually
+	if err = r.individualRuns(ctx, commandStrings); err != nil {
+		return
+	}
+
+	if !r.opts.NoIndvSched {
+		if err = r.individualCgroupRuns(ctx, commandStrings); err != nil {
+			return
+		}
+	}
+
+	if r.opts.NoCoSched {
+		return
+	}
+
+	if err = r.coSchedRuns(ctx, commandPairs); err != nil {
+		return
+	}
+
+	err = r.coSchedCgroupRuns(ctx, commandPairs)
+	return
+}
+
+func (r *RunnerT) cleanup() error {
+	log.Infoln("Benchmark runs complete")
+
+	if r.opts.Output == "" {
+		return nil
+	}
+
+	err := r.stats.StoreToFile(r.opts.Output)
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"file": r.opts.Output,
+		}).Errorln("Error store measurements")
+
+		j, _ := r.stats.CreateJSON()
+		log.Infoln(string(j))
+		return err
+	}
+
+	filename := r.opts.Output
+	absfilename, err := filepath.Abs(filename)
+	if err == nil {
+		filename = absfilename
+	}
+	hostname, err := os.Hostname()
+	if err == nil {
+		filename = hostname + ":" + filename
+	}
+
+	log.WithField("file", filename).Infoln("Result file written")
+	return nil
+}
+
+func (r *RunnerT) individualRuns(ctx context.Context, commands []string) error {
+
+	log.Infoln("Running apps individually")
+
+	// run app individually without CAT (if CAT was requested)
+	for _, c := range commands {
+		catConfig := [2]uint64{stats.NoCATMask, stats.NoCATMask}
+
+		log.WithFields(log.Fields{
+			"app": c,
+		}).Infoln("Running app")
+		runtime, err := r.runSingle(ctx, c, catConfig, "")
+		if err != nil {
+			return fmt.Errorf("Error running app %v: %v", c, err)
+		}
+
+		stat := r.stats.AddReferenceRuntime(c, runtime)
+		r.printStats(c, stat, catConfig[0], "")
+		r.progress([]string{c}, catConfig[:1], "", []stats.RuntimeT{stat})
+	}
+
+	if !r.opts.CAT || r.opts.NoIndvSched {
+		return nil
+	}
+
+	if err := r.setupCAT(); err != nil {
+		return fmt.Errorf("Error setting up CAT: %v", err)
+	}
+	defer r.resetCAT()
+
+	for _, c := range commands {
+
+		log.WithFields(log.Fields{
+			"app": c,
+		}).Infoln("Running app with CAT")
+
+		for _, catConfig := range r.catPairs {
+			runtime, err := r.runSingle(ctx, c, catConfig, "")
+			if err != nil {
+				return fmt.Errorf("Error running app %v: %v", c, err)
+			}
+
+			stat := r.stats.AddCATRuntime(c, catConfig[0], runtime)
+			r.printStats(c, stat, catConfig[0], "")
+			r.progress([]string{c}, catConfig[:1], "", []stats.RuntimeT{stat})
+		}
+	}
+
+	log.Infoln("Individual runs done")
+	return nil
+}
+
+func (r *RunnerT) coSchedRuns(ctx context.Context, commandPairs [][2]string) error {
+	log.Infoln("Executing the following command pairs")
+	for i, c := range commandPairs {
+		log.WithFields(log.Fields{
+			"app0": c[0],
+			"app1": c[1],
+		}).Infof("%v", i)
+	}
+
+	// run co-scheduling *without* cat
+	for i, c := range commandPairs {
+		log.WithFields(log.Fields{
+			"app0": c[0],
+			"app1": c[1],
+		}).Infof("Running pair %v", i)
+
+		catConfig := [2]uint64{stats.NoCATMask, stats.NoCATMask}
+		runtimes, err := r.runPair(ctx, c, catConfig, "")
+		if err != nil {
+			return fmt.Errorf("Error running pair %v, %v: %v", c[0], c[1], err)
+		}
+
+		r.processRuntime(c, catConfig, "", runtimes)
+	}
+
+	if !r.opts.CAT {
+		return nil
+	}
+
+	if err := r.setupCAT(); err != nil {
+		return fmt.Errorf("Error setting up CAT: %v", err)
+	}
+	defer r.resetCAT()
+
+	for i, c := range commandPairs {
+		log.WithFields(log.Fields{
+			"app0": c[0],
+			"app1": c[1],
+		}).Infof("Running pair %v", i)
+
+		for _, catConfig := range r.catPairs {
+			runtimes, err := r.runPair(ctx, c, catConfig, "")
+			if err != nil {
+				return fmt.Errorf("Error running pair %v, %v: %v", c[0], c[1], err)
+			}
+
+			r.processRuntime(c, catConfig, "", runtimes)
+		}
+	}
+	return nil
+}
+
+func (r *RunnerT) processRuntime(cPair [2]string, catMasks [2]uint64, cgroupSetting string, runtimes [][]stats.DataPerRun) {
+	var stat [2]stats.RuntimeT
+
+	for i, runtime := range runtimes {
+		if cgroupSetting != "" {
+			stat[i] = r.stats.AddCoSchedCgroupRuntime(cPair[i], cPair[(i+1)%2], cgroupSetting, runtime)
+		} else if catMasks[0] != 0 && catMasks[1] != 0 {
+			stat[i] = r.stats.AddCoSchedCATRuntime(cPair[i], cPair[(i+1)%2], catMasks[i], runtime)
+		} else {
+			stat[i] = r.stats.AddCoSchedRuntime(cPair[i], cPair[(i+1)%2], runtime)
+		}
+
+		r.printStats(cPair[i], stat[i], catMasks[i], cgroupSetting)
+	}
+
+	r.progress(cPair[:], catMasks[:], cgroupSetting, stat[:])
+}
+
+// counts the finished measurement and passes it to r.opts.OnProgress
+func (r *RunnerT) progress(apps []string, catMasks []uint64, cgroupSetting string, stat []stats.RuntimeT) {
+	r.done++
+	if r.opts.OnProgress == nil {
+		return
+	}
+	r.opts.OnProgress(ProgressT{Done: r.done, Total: r.total, Apps: apps, CATMasks: catMasks, Cgroup: cgroupSetting, Stats: stat})
+}
+
+func (r *RunnerT) printStats(c string, stat stats.RuntimeT, catMask uint64, cgroupSetting string) {
+	ref := r.stats.Runtime(stats.ConfigT{App: c})
+	slowdown := r.stats.Slowdown(&stat, ref, r.opts.SlowdownMetric)
+
+	fields := log.Fields{
+		"Ø":        fmt.Sprintf("%9.2f", stat.Mean),
+		"median":   fmt.Sprintf("%9.2f", stat.Median),
+		"σ":        fmt.Sprintf("%1.6f", stat.Stddev),
+		"σ²":       fmt.Sprintf("%1.6f", stat.Vari),
+		"runs":     fmt.Sprintf("%3d", stat.Runs),
+		"outliers": fmt.Sprintf("%3d", stat.Outliers),
+		"CI":       fmt.Sprintf("±%1.4f", stat.CIRelHalfWidth),
+		"CAT":      fmt.Sprintf("%6x", catMask),
+		"slowdown": fmt.Sprintf("%1.6f", slowdown),
+	}
+	if cgroupSetting != "" {
+		fields["cgroup"] = cgroupSetting
+	}
+	if ci, ok := r.stats.BootstrapSlowdownCI(&stat, ref, r.opts.SlowdownMetric, r.opts.CILevel); ok {
+		fields["slowdown CI"] = fmt.Sprintf("[%1.4f, %1.4f]", ci[0], ci[1])
+	}
+	if r.opts.SlowdownMetric != stats.RuntimeMetric {
+		mean, _ := stat.MetricStats(r.opts.SlowdownMetric)
+		fields[r.opts.SlowdownMetric] = fmt.Sprintf("%9.2f %v", mean, r.stats.MetricUnit(r.opts.SlowdownMetric))
+	}
+
+	log.WithFields(fields).Infof("%v", commands.Pretty(c))
+}
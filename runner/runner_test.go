@@ -0,0 +1,395 @@
+package runner
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jbreitbart/coBench/cgroup"
+	"github.com/jbreitbart/coBench/launcher"
+	"github.com/jbreitbart/coBench/stats"
+)
+
+func testOptions(t *testing.T) OptionsT {
+	o := DefaultOptions()
+	o.CPUs = [2]string{"0", "0"}
+	o.Threads = "1"
+	o.LogDir = t.TempDir()
+	return o
+}
+
+func TestRun(t *testing.T) {
+	o := testOptions(t)
+
+	var mutex sync.Mutex
+	runs := make(map[[2]string]int)
+	o.OnRun = func(run RunT) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		runs[[2]string{run.App, run.CoSched}]++
+	}
+	var progress []ProgressT
+	o.OnProgress = func(p ProgressT) {
+		progress = append(progress, p)
+	}
+
+	r, err := New(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = r.Run(context.Background(), []string{"sleep 0.01", "true"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, config := range [][2]string{{"sleep 0.01", ""}, {"true", ""}, {"sleep 0.01", "true"}, {"true", "sleep 0.01"}} {
+		if runs[config] < o.Runs {
+			t.Errorf("%v has %v runs, expected at least %v", config, runs[config], o.Runs)
+		}
+	}
+
+	if len(progress) != 3 {
+		t.Fatalf("%v progress updates, expected 3", len(progress))
+	}
+	for i, p := range progress {
+		if p.Done != i+1 || p.Total != 3 || len(p.Apps) != len(p.Stats) {
+			t.Errorf("Unexpected progress %+v", p)
+		}
+	}
+	if apps := progress[2].Apps; !reflect.DeepEqual(apps, []string{"sleep 0.01", "true"}) {
+		t.Errorf("Pair progress has apps %v", apps)
+	}
+
+	// a second run starts with its own dataset
+	first := r.Stats()
+	if err = r.Run(context.Background(), []string{"true", "echo"}); err != nil {
+		t.Fatal(err)
+	}
+	if first.Runtime(stats.ConfigT{App: "echo"}) != nil || r.Stats().Runtime(stats.ConfigT{App: "sleep 0.01"}) != nil {
+		t.Errorf("Runs share their measurements")
+	}
+}
+
+func TestRunCancel(t *testing.T) {
+	r, err := New(testOptions(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = r.Run(ctx, []string{"sleep 10", "sleep 20"})
+	if err == nil {
+		t.Fatalf("Cancelled run succeeded")
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("Cancelled run took %v", d)
+	}
+}
+
+func TestRunNoIndvSched(t *testing.T) {
+	o := testOptions(t)
+	o.NoIndvSched = true
+
+	r, err := New(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = r.Run(context.Background(), []string{"true", "echo"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Stats().Runtime(stats.ConfigT{App: "true", CoSched: "echo"}) == nil {
+		t.Errorf("No co-scheduling runtime without individual runs")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	o := DefaultOptions()
+	if err := o.Validate(); err != nil {
+		t.Errorf("Default options are invalid: %v", err)
+	}
+
+	o.Runs = 0
+	if _, err := New(o); err == nil {
+		t.Errorf("New accepted runs = 0")
+	}
+
+	o = DefaultOptions()
+	o.CIWidth = 0.01
+	o.VarianceDiff = 0.1
+	if _, err := New(o); err == nil {
+		t.Errorf("New accepted ci and variance")
+	}
+}
+
+func TestGenerateCatConfigs(t *testing.T) {
+	o := DefaultOptions()
+	o.CAT = true
+	r := RunnerT{opts: o}
+
+	expected := [][2]uint64{{0x3, 0xfc}, {0xf, 0xf0}, {0x3f, 0xc0}}
+	if configs := r.generateCatConfigs(2, 8); !reflect.DeepEqual(configs, expected) {
+		t.Errorf("generateCatConfigs(2, 8) = %x, expected %x", configs, expected)
+	}
+
+	r.opts.InverseCAT = true
+	expected = [][2]uint64{{0xfc, 0x3}, {0xf0, 0xf}, {0xc0, 0x3f}}
+	if configs := r.generateCatConfigs(2, 8); !reflect.DeepEqual(configs, expected) {
+		t.Errorf("inverse generateCatConfigs(2, 8) = %x, expected %x", configs, expected)
+	}
+}
+
+// creates a resctrl file system with a 4 bit capacity bitmask and the groups of coBench
+func fakeResctrl(t *testing.T) string {
+	root := t.TempDir()
+	files := map[string]string{
+		"info/L3/min_cbm_bits": "2\n",
+		"info/L3/cbm_mask":     "f\n",
+	}
+	for _, dir := range catDirs(root) {
+		for _, name := range []string{"cpus", "schemata", "tasks"} {
+			files[dir[len(root):]+"/"+name] = ""
+		}
+	}
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Dir(root+"/"+name), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(root+"/"+name, []byte(content), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+// containers with CAT are moved into the resctrl group of their CPUs
+func TestRunContainerCAT(t *testing.T) {
+	runtime, err := filepath.Abs("../launcher/testdata/fake-runtime")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FAKE_RUNTIME_STATE", t.TempDir())
+
+	o := testOptions(t)
+	// setupCAT only supports ranges
+	o.CPUs = [2]string{"0-0", "0-0"}
+	o.CAT = true
+	o.ResctrlPath = fakeResctrl(t)
+	if o.Launcher, err = launcher.Parse("container image=test runtime=" + runtime); err != nil {
+		t.Fatal(err)
+	}
+	var mutex sync.Mutex
+	var runs []RunT
+	o.OnRun = func(run RunT) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		runs = append(runs, run)
+	}
+
+	r, err := New(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = r.Run(context.Background(), []string{"true", "echo"}); err != nil {
+		t.Fatal(err)
+	}
+
+	catRuns := 0
+	for _, run := range runs {
+		if run.Data.Runtime <= 0 {
+			t.Errorf("Run without timing %+v", run)
+		}
+		if run.Data.Rusage != nil {
+			t.Errorf("Run with the rusage of the runtime CLI %+v", run)
+		}
+		if run.CATMask != 0 {
+			catRuns++
+		}
+	}
+	if catRuns == 0 {
+		t.Errorf("No runs with CAT in %v", runs)
+	}
+
+	for i, dir := range catDirs(o.ResctrlPath) {
+		tasks, _ := ioutil.ReadFile(dir + "/tasks")
+		if len(tasks) == 0 {
+			t.Errorf("No container moved into group %v", i)
+		}
+	}
+	if schemata, _ := ioutil.ReadFile(catDirs(o.ResctrlPath)[1] + "/schemata"); string(schemata) != "L3:0=c;1=c\n" {
+		t.Errorf("Unexpected schemata %q", schemata)
+	}
+}
+
+// a failing application fails the benchmark
+func TestRunFailure(t *testing.T) {
+	r, err := New(testOptions(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = r.Run(context.Background(), []string{"true", "exit 3"}); err == nil {
+		t.Errorf("Benchmark with a failing application succeeded")
+	}
+}
+
+// every run is executed in its own cgroup below the slot with the limits of the setting
+func TestRunCgroups(t *testing.T) {
+	o := testOptions(t)
+	o.CgroupRoot = t.TempDir()
+	o.Cgroups = []stats.CgroupSettingT{
+		{Name: "mem", Limits: [2]stats.CgroupLimitsT{{"memory.max": "1G"}, {"memory.max": "2G"}}},
+		{Name: "cpu", Limits: [2]stats.CgroupLimitsT{{"cpu.max": "50000 100000"}, nil}},
+	}
+	var mutex sync.Mutex
+	var runs []RunT
+	o.OnRun = func(run RunT) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		runs = append(runs, run)
+	}
+	var progress []ProgressT
+	o.OnProgress = func(p ProgressT) {
+		progress = append(progress, p)
+	}
+
+	r, err := New(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := `cat "$` + cgroup.EnvVar + `/cgroup.procs" "$` + cgroup.EnvVar + `/../memory.max" 2>/dev/null; true`
+	if err = r.Run(context.Background(), []string{app, "true"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(progress) != 9 || progress[8].Total != 9 || progress[8].Cgroup != "cpu" {
+		t.Errorf("Unexpected progress %+v", progress)
+	}
+	settings := make(map[string]int)
+	for _, run := range runs {
+		settings[run.Cgroup]++
+		if run.Data.Cgroup == nil {
+			t.Errorf("Run without cgroup statistics %+v", run)
+		}
+		if run.App != app {
+			continue
+		}
+		// the pid written by the shell and the limit of the 1st slot
+		lines := strings.Fields(run.Data.Output)
+		if len(lines) == 0 {
+			t.Errorf("Run not moved into a cgroup: %+v", run)
+		} else if expected := map[string]string{"": "", "cpu": "", "mem": "1G"}[run.Cgroup]; strings.Join(lines[1:], "") != expected {
+			t.Errorf("Run with setting %q sees the memory.max %q", run.Cgroup, lines[1:])
+		}
+	}
+	for _, setting := range []string{"", "mem", "cpu"} {
+		if settings[setting] == 0 {
+			t.Errorf("No runs with setting %q: %v", setting, settings)
+		}
+	}
+	if r.Stats().Runtime(stats.ConfigT{App: app, CoSched: "true", Cgroup: "mem"}) == nil {
+		t.Errorf("No co-scheduling runtime with setting mem")
+	}
+
+	entries, _ := ioutil.ReadDir(o.CgroupRoot)
+	for _, e := range entries {
+		if e.IsDir() {
+			t.Errorf("cgroup %v was not removed", e.Name())
+		}
+	}
+}
+
+// writes the files below a temporary directory and returns it
+func fakeFiles(t *testing.T, files map[string]string) string {
+	root := t.TempDir()
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(root, name)), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(root, name), []byte(content), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+// cache of a CPU in sysfs
+func sysCache(cpu int, index int, level string, size string, ways string, shared string) map[string]string {
+	dir := "cpu" + strconv.Itoa(cpu) + "/cache/index" + strconv.Itoa(index) + "/"
+	return map[string]string{dir + "level": level + "\n", dir + "size": size + "\n", dir + "ways_of_associativity": ways + "\n",
+		dir + "shared_cpu_list": shared + "\n"}
+}
+
+func TestReadL3Info(t *testing.T) {
+	merge := func(caches ...map[string]string) map[string]string {
+		ret := make(map[string]string)
+		for _, c := range caches {
+			for k, v := range c {
+				ret[k] = v
+			}
+		}
+		return ret
+	}
+
+	expected := []struct {
+		name  string
+		files map[string]string
+		hw    stats.HardwareT
+		err   bool
+	}{
+		{"2 sockets in K", merge(sysCache(0, 0, "1", "32K", "8", "0"), sysCache(0, 3, "3", "30720K", "20", "0-1"), sysCache(1, 3, "3", "30720K", "20", "0-1"),
+			sysCache(2, 3, "3", "30720K", "20", "2-3"), sysCache(3, 3, "3", "30720K", "20", "2-3")),
+			stats.HardwareT{L3Size: 30 << 20, L3Ways: 20, L3Caches: 2}, false},
+		{"M", merge(sysCache(0, 3, "3", "32M", "16", "0-7")), stats.HardwareT{L3Size: 32 << 20, L3Ways: 16, L3Caches: 1}, false},
+		{"bytes", merge(sysCache(0, 2, "3", "1048576", "4", "0")), stats.HardwareT{L3Size: 1 << 20, L3Ways: 4, L3Caches: 1}, false},
+		{"no L3", merge(sysCache(0, 0, "1", "32K", "8", "0"), sysCache(0, 2, "2", "1024K", "16", "0")), stats.HardwareT{}, true},
+		{"invalid size", merge(sysCache(0, 3, "3", "12X", "16", "0")), stats.HardwareT{}, true},
+		{"invalid ways", merge(sysCache(0, 3, "3", "12M", "many", "0")), stats.HardwareT{}, true},
+	}
+
+	for _, e := range expected {
+		hw, err := readL3Info(fakeFiles(t, e.files))
+		if (err != nil) != e.err {
+			t.Errorf("%v: readL3Info returned the error %v", e.name, err)
+			continue
+		}
+		if !e.err && hw != e.hw {
+			t.Errorf("%v: readL3Info = %+v, expected %+v", e.name, hw, e.hw)
+		}
+	}
+}
+
+func TestReadCPUModel(t *testing.T) {
+	cpuInfo := "processor\t: 0\nvendor_id\t: GenuineIntel\nmodel name\t: Intel(R) Xeon(R) CPU E5-2630 v4 @ 2.20GHz\n\n" +
+		"processor\t: 1\nmodel name\t: Intel(R) Xeon(R) CPU E5-2630 v4 @ 2.20GHz\n"
+	root := fakeFiles(t, map[string]string{"cpuinfo": cpuInfo, "empty": "processor\t: 0\n"})
+
+	if model, err := readCPUModel(root + "/cpuinfo"); err != nil || model != "Intel(R) Xeon(R) CPU E5-2630 v4 @ 2.20GHz" {
+		t.Errorf("readCPUModel = %q, %v", model, err)
+	}
+	if _, err := readCPUModel(root + "/empty"); err == nil {
+		t.Errorf("readCPUModel accepted a cpuinfo without model name")
+	}
+}
+
+func TestReadCATInfo(t *testing.T) {
+	for mask, bits := range map[string]uint64{"f": 4, "7ff": 11, "fffff": 20} {
+		root := fakeFiles(t, map[string]string{"info/L3/min_cbm_bits": "1\n", "info/L3/cbm_mask": mask + "\n"})
+		minBits, numBits, err := ReadCATInfo(root)
+		if err != nil || minBits != 1 || numBits != bits {
+			t.Errorf("ReadCATInfo with cbm_mask %v = %v, %v, %v, expected 1, %v", mask, minBits, numBits, err, bits)
+		}
+	}
+
+	if _, _, err := ReadCATInfo(t.TempDir()); err == nil {
+		t.Errorf("ReadCATInfo without resctrl succeeded")
+	}
+}
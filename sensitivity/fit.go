@@ -0,0 +1,184 @@
+package sensitivity
+
+import (
+	"fmt"
+	"math"
+)
+
+// LineT is y = Intercept + Slope * x
+type LineT struct {
+	Intercept float64
+	Slope     float64
+}
+
+// At evaluates the line at x
+func (l LineT) At(x float64) float64 {
+	return l.Intercept + l.Slope*x
+}
+
+// PiecewiseLinearT are two lines meeting at the knee
+type PiecewiseLinearT struct {
+	Knee  float64
+	Left  LineT
+	Right LineT
+	// sum of squared errors
+	SSE float64
+}
+
+// At evaluates the fit at x
+func (p PiecewiseLinearT) At(x float64) float64 {
+	if x <= p.Knee {
+		return p.Left.At(x)
+	}
+	return p.Right.At(x)
+}
+
+// PowerLawT is y = A * x^B
+type PowerLawT struct {
+	A   float64
+	B   float64
+	SSE float64
+}
+
+// At evaluates the fit at x
+func (p PowerLawT) At(x float64) float64 {
+	return p.A * math.Pow(x, p.B)
+}
+
+// FitLine fits a line with least squares
+func FitLine(x, y []float64) (LineT, error) {
+	if len(x) != len(y) || len(x) < 2 {
+		return LineT{}, fmt.Errorf("At least 2 points required")
+	}
+
+	n := float64(len(x))
+	sx, sy, sxx, sxy := 0.0, 0.0, 0.0, 0.0
+	for i := range x {
+		sx += x[i]
+		sy += y[i]
+		sxx += x[i] * x[i]
+		sxy += x[i] * y[i]
+	}
+	d := n*sxx - sx*sx
+	if d == 0 {
+		return LineT{}, fmt.Errorf("All x values are identical")
+	}
+
+	slope := (n*sxy - sx*sy) / d
+	return LineT{Intercept: (sy - slope*sx) / n, Slope: slope}, nil
+}
+
+// FitPiecewiseLinear fits two continuous lines meeting at one of the x values. x must be sorted.
+func FitPiecewiseLinear(x, y []float64) (PiecewiseLinearT, error) {
+	if len(x) != len(y) || len(x) < 3 {
+		return PiecewiseLinearT{}, fmt.Errorf("At least 3 points required")
+	}
+
+	best := PiecewiseLinearT{SSE: math.Inf(1)}
+	for k := 1; k < len(x)-1; k++ {
+		// both lines go through the knee, so they are fitted through (x[k], yk) for the best yk
+		p, ok := fitHinge(x, y, k)
+		if ok && p.SSE < best.SSE {
+			best = p
+		}
+	}
+
+	if math.IsInf(best.SSE, 1) {
+		return best, fmt.Errorf("No piecewise linear fit found")
+	}
+	return best, nil
+}
+
+// continuous fit y = c + l*(min(x, xk) - xk) + r*(max(x, xk) - xk) with least squares
+func fitHinge(x, y []float64, k int) (PiecewiseLinearT, bool) {
+	xk := x[k]
+
+	// normal equations of 3 unknowns c, l, r
+	var a [3][3]float64
+	var b [3]float64
+	for i := range x {
+		f := [3]float64{1, math.Min(x[i], xk) - xk, math.Max(x[i], xk) - xk}
+		for r := 0; r < 3; r++ {
+			for c := 0; c < 3; c++ {
+				a[r][c] += f[r] * f[c]
+			}
+			b[r] += f[r] * y[i]
+		}
+	}
+	sol, ok := solve3(a, b)
+	if !ok {
+		return PiecewiseLinearT{}, false
+	}
+
+	c, l, r := sol[0], sol[1], sol[2]
+	ret := PiecewiseLinearT{
+		Knee:  xk,
+		Left:  LineT{Intercept: c - l*xk, Slope: l},
+		Right: LineT{Intercept: c - r*xk, Slope: r},
+	}
+	for i := range x {
+		d := ret.At(x[i]) - y[i]
+		ret.SSE += d * d
+	}
+	return ret, true
+}
+
+// Gaussian elimination with partial pivoting
+func solve3(a [3][3]float64, b [3]float64) ([3]float64, bool) {
+	for col := 0; col < 3; col++ {
+		pivot := col
+		for r := col + 1; r < 3; r++ {
+			if math.Abs(a[r][col]) > math.Abs(a[pivot][col]) {
+				pivot = r
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return [3]float64{}, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		b[col], b[pivot] = b[pivot], b[col]
+
+		for r := col + 1; r < 3; r++ {
+			f := a[r][col] / a[col][col]
+			for c := col; c < 3; c++ {
+				a[r][c] -= f * a[col][c]
+			}
+			b[r] -= f * b[col]
+		}
+	}
+
+	var x [3]float64
+	for r := 2; r >= 0; r-- {
+		x[r] = b[r]
+		for c := r + 1; c < 3; c++ {
+			x[r] -= a[r][c] * x[c]
+		}
+		x[r] /= a[r][r]
+	}
+	return x, true
+}
+
+// FitPowerLaw fits y = A * x^B with least squares in log-log space. All values must be > 0.
+func FitPowerLaw(x, y []float64) (PowerLawT, error) {
+	lx := make([]float64, len(x))
+	ly := make([]float64, len(y))
+	for i := range x {
+		if x[i] <= 0 || y[i] <= 0 {
+			return PowerLawT{}, fmt.Errorf("Power law fits require positive values")
+		}
+		lx[i] = math.Log(x[i])
+		ly[i] = math.Log(y[i])
+	}
+
+	l, err := FitLine(lx, ly)
+	if err != nil {
+		return PowerLawT{}, err
+	}
+
+	ret := PowerLawT{A: math.Exp(l.Intercept), B: l.Slope}
+	for i := range x {
+		d := ret.At(x[i]) - y[i]
+		ret.SSE += d * d
+	}
+	return ret, nil
+}
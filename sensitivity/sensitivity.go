@@ -0,0 +1,98 @@
+// Package sensitivity fits the cache sensitivity curve of an application measured with different CAT allocations
+package sensitivity
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jbreitbart/coBench/predict"
+)
+
+// Classes of applications
+const (
+	// benefits from additional cache up to its working set
+	SensitiveClass = "cache-sensitive"
+	// runs equally fast with the smallest allocation
+	InsensitiveClass = "cache-insensitive"
+	// does not benefit from the cache, but puts a high pressure on it
+	StreamingClass = "streaming"
+)
+
+// DefaultThreshold is the default minimum slowdown - 1 at the smallest allocation of cache-sensitive applications
+const DefaultThreshold = 0.05
+
+// OptionsT configures the classification
+type OptionsT struct {
+	// applications with a smaller sensitivity score are cache-insensitive
+	Threshold float64
+	// cache-insensitive applications with at least this pressure are streaming. 0 disables the streaming class.
+	StreamingPressure float64
+}
+
+// ResultT is the sensitivity analysis of one application
+type ResultT struct {
+	App string
+	// measured curve, slowdown compared to the whole cache by CAT bits
+	Bits      []float64
+	Slowdowns []float64
+	// number of CAT bits of the whole cache
+	CacheBits int
+	Piecewise PiecewiseLinearT
+	PowerLaw  PowerLawT
+	// CAT bits after which additional bits stop helping, i.e. the effective working set
+	Knee float64
+	// slowdown at the smallest allocation - 1
+	Score    float64
+	Pressure float64
+	Class    string
+}
+
+// Analyze fits the CAT curve of the profile and classifies the application
+func Analyze(profile predict.ProfileT, opts OptionsT) (*ResultT, error) {
+	if opts.Threshold == 0 {
+		opts.Threshold = DefaultThreshold
+	}
+
+	ret := ResultT{App: profile.App, CacheBits: profile.CacheBits, Pressure: profile.Pressure}
+
+	var keys []int
+	for k := range profile.CATCurve {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	for _, k := range keys {
+		ret.Bits = append(ret.Bits, float64(k))
+		ret.Slowdowns = append(ret.Slowdowns, profile.CATCurve[k])
+	}
+
+	var err error
+	ret.Piecewise, err = FitPiecewiseLinear(ret.Bits, ret.Slowdowns)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot fit the CAT curve of %v: %v", profile.App, err)
+	}
+	ret.PowerLaw, err = FitPowerLaw(ret.Bits, ret.Slowdowns)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot fit the CAT curve of %v: %v", profile.App, err)
+	}
+
+	ret.Score = ret.Slowdowns[0] - 1
+
+	switch {
+	case ret.Score < opts.Threshold:
+		ret.Class = InsensitiveClass
+		if opts.StreamingPressure > 0 && ret.Pressure >= opts.StreamingPressure {
+			ret.Class = StreamingClass
+		}
+		// the working set already fits into the smallest allocation
+		ret.Knee = ret.Bits[0]
+	case ret.Piecewise.At(ret.Piecewise.Knee)-ret.Piecewise.At(float64(ret.CacheBits)) >= opts.Threshold:
+		ret.Class = SensitiveClass
+		// still improving after the knee, the working set does not fit into the cache
+		ret.Knee = float64(ret.CacheBits)
+	default:
+		ret.Class = SensitiveClass
+		ret.Knee = ret.Piecewise.Knee
+	}
+
+	return &ret, nil
+}
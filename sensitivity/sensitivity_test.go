@@ -0,0 +1,71 @@
+package sensitivity
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jbreitbart/coBench/predict"
+)
+
+func TestFitPiecewiseLinear(t *testing.T) {
+	// slope -1 up to 4, flat afterwards
+	x := []float64{1, 2, 3, 4, 5, 6, 7}
+	y := []float64{5, 4, 3, 2, 2, 2, 2}
+
+	p, err := FitPiecewiseLinear(x, y)
+	if err != nil {
+		t.Fatalf("Fit failed: %v", err)
+	}
+	if p.Knee != 4 || math.Abs(p.Left.Slope+1) > 1e-9 || math.Abs(p.Right.Slope) > 1e-9 || p.SSE > 1e-9 {
+		t.Errorf("Unexpected fit %+v", p)
+	}
+
+	if _, err = FitPiecewiseLinear(x[:2], y[:2]); err == nil {
+		t.Errorf("Fit with 2 points must fail")
+	}
+}
+
+func TestFitPowerLaw(t *testing.T) {
+	x := []float64{1, 2, 4, 8}
+	y := []float64{3, 1.5, 0.75, 0.375}
+
+	p, err := FitPowerLaw(x, y)
+	if err != nil {
+		t.Fatalf("Fit failed: %v", err)
+	}
+	if math.Abs(p.A-3) > 1e-9 || math.Abs(p.B+1) > 1e-9 {
+		t.Errorf("Unexpected fit %+v", p)
+	}
+
+	if _, err = FitPowerLaw([]float64{0, 1}, []float64{1, 1}); err == nil {
+		t.Errorf("Fit with x = 0 must fail")
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	sensitive := predict.ProfileT{App: "s", CacheBits: 10, Pressure: math.NaN(),
+		CATCurve: map[int]float64{2: 1.6, 4: 1.2, 6: 1, 8: 1, 10: 1}}
+	r, err := Analyze(sensitive, OptionsT{})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if r.Class != SensitiveClass || r.Knee != 6 || math.Abs(r.Score-0.6) > 1e-9 {
+		t.Errorf("Unexpected result %+v", r)
+	}
+
+	// still improving with the whole cache
+	hungry := predict.ProfileT{App: "h", CacheBits: 10, Pressure: math.NaN(),
+		CATCurve: map[int]float64{2: 1.8, 4: 1.6, 6: 1.4, 8: 1.2, 10: 1}}
+	if r, _ = Analyze(hungry, OptionsT{}); r.Class != SensitiveClass || r.Knee != 10 {
+		t.Errorf("Unexpected result %+v", r)
+	}
+
+	flat := predict.ProfileT{App: "f", CacheBits: 10, Pressure: 100,
+		CATCurve: map[int]float64{2: 1.01, 4: 1, 6: 1, 8: 1, 10: 1}}
+	if r, _ = Analyze(flat, OptionsT{}); r.Class != InsensitiveClass || r.Knee != 2 {
+		t.Errorf("Unexpected result %+v", r)
+	}
+	if r, _ = Analyze(flat, OptionsT{StreamingPressure: 50}); r.Class != StreamingClass {
+		t.Errorf("Unexpected result %+v", r)
+	}
+}
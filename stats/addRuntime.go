@@ -1,89 +1,53 @@
 package stats
 
 import (
-	"log"
 	"math/bits"
 )
 
-func checkIfReferenceExists(application string) {
-	if _, ok := runtimeStats.Runtimes[application]; !ok {
-		log.Fatalln("Error while inserting CAT runtime. Application key does not exist. Call AddReferenceRuntime() first.")
-	}
+// adds the runs of the configuration measured with the CAT mask and returns its updated runtime. The entry of the
+// application is created if needed, e.g. co-scheduling without individual runs.
+func (s *StatsT) addRuntime(c ConfigT, CATMask uint64, data []DataPerRun) RuntimeT {
+	s.extractMetrics(c.App, data)
+	s.addRuns(c, CATMask, data)
+	return *s.Runtime(c)
 }
 
 // AddReferenceRuntime adds the individual runtime without CAT
-func AddReferenceRuntime(application string, data []DataPerRun) RuntimeT {
-	if runtimeStats.Runtimes == nil {
-		runtimeStats.Runtimes = make(map[string]*RuntimePerAppT, 1)
-	}
-
-	var old RuntimeT
-
-	if runtimeStats.Runtimes[application] != nil {
-		old = runtimeStats.Runtimes[application].ReferenceRuntimes
-	}
-	old.update(NoCATMask, data)
-
-	var temp RuntimePerAppT
-	temp.ReferenceRuntimes = old
-	runtimeStats.Runtimes[application] = &temp
-
-	return old
+func (s *StatsT) AddReferenceRuntime(application string, data []DataPerRun) RuntimeT {
+	return s.addRuntime(ConfigT{App: application}, NoCATMask, data)
 }
 
 // AddCATRuntime adds the individual runtime with CAT
-func AddCATRuntime(application string, CATMask uint64, data []DataPerRun) RuntimeT {
-	checkIfReferenceExists(application)
-
-	if runtimeStats.Runtimes[application].CATRuntimes == nil {
-		temp := make(map[int]RuntimeT, 1)
-		runtimeStats.Runtimes[application].CATRuntimes = &temp
-	}
-
-	key := bits.OnesCount64(CATMask)
-	old := (*runtimeStats.Runtimes[application].CATRuntimes)[key]
-	old.update(CATMask, data)
-
-	(*runtimeStats.Runtimes[application].CATRuntimes)[key] = old
-
-	return old
+func (s *StatsT) AddCATRuntime(application string, CATMask uint64, data []DataPerRun) RuntimeT {
+	return s.addRuntime(ConfigT{App: application, CATBits: bits.OnesCount64(CATMask)}, CATMask, data)
 }
 
 // AddCoSchedRuntime adds the co-scheduling runtime of 'application' co-scheduled with coSchedApplication without CAT
-func AddCoSchedRuntime(application string, coSchedApplication string, data []DataPerRun) RuntimeT {
-	checkIfReferenceExists(application)
-
-	if runtimeStats.Runtimes[application].CoSchedRuntimes == nil {
-		temp := make(map[string]RuntimeT, 1)
-		runtimeStats.Runtimes[application].CoSchedRuntimes = &temp
-	}
-
-	old := (*runtimeStats.Runtimes[application].CoSchedRuntimes)[coSchedApplication]
-	old.update(NoCATMask, data)
-
-	(*runtimeStats.Runtimes[application].CoSchedRuntimes)[coSchedApplication] = old
-
-	return old
+func (s *StatsT) AddCoSchedRuntime(application string, coSchedApplication string, data []DataPerRun) RuntimeT {
+	return s.addRuntime(ConfigT{App: application, CoSched: coSchedApplication}, NoCATMask, data)
 }
 
 // AddCoSchedCATRuntime adds the co-scheduling runtime of 'application' co-scheduled with coSchedApplication with CAT
-func AddCoSchedCATRuntime(application string, coSchedApplication string, CATMask uint64, data []DataPerRun) RuntimeT {
-	checkIfReferenceExists(application)
+func (s *StatsT) AddCoSchedCATRuntime(application string, coSchedApplication string, CATMask uint64, data []DataPerRun) RuntimeT {
+	return s.addRuntime(ConfigT{App: application, CoSched: coSchedApplication, CATBits: bits.OnesCount64(CATMask)}, CATMask, data)
+}
 
-	if runtimeStats.Runtimes[application].CoSchedCATRuntimes == nil {
-		temp := make(map[string]map[int]RuntimeT, 1)
-		runtimeStats.Runtimes[application].CoSchedCATRuntimes = &temp
-	}
-	if (*runtimeStats.Runtimes[application].CoSchedCATRuntimes)[coSchedApplication] == nil {
-		temp := make(map[int]RuntimeT, 1)
-		(*runtimeStats.Runtimes[application].CoSchedCATRuntimes)[coSchedApplication] = temp
-	}
+// AddReferenceRuntime adds the individual runtime without CAT to the stats of the package
+func AddReferenceRuntime(application string, data []DataPerRun) RuntimeT {
+	return runtimeStats.AddReferenceRuntime(application, data)
+}
 
-	key := bits.OnesCount64(CATMask)
-	old := (*runtimeStats.Runtimes[application].CoSchedCATRuntimes)[coSchedApplication][key]
-	old.update(CATMask, data)
+// AddCATRuntime adds the individual runtime with CAT to the stats of the package
+func AddCATRuntime(application string, CATMask uint64, data []DataPerRun) RuntimeT {
+	return runtimeStats.AddCATRuntime(application, CATMask, data)
+}
 
-	(*runtimeStats.Runtimes[application].CoSchedCATRuntimes)[coSchedApplication][key] = old
+// AddCoSchedRuntime adds the co-scheduling runtime without CAT to the stats of the package
+func AddCoSchedRuntime(application string, coSchedApplication string, data []DataPerRun) RuntimeT {
+	return runtimeStats.AddCoSchedRuntime(application, coSchedApplication, data)
+}
 
-	return old
+// AddCoSchedCATRuntime adds the co-scheduling runtime with CAT to the stats of the package
+func AddCoSchedCATRuntime(application string, coSchedApplication string, CATMask uint64, data []DataPerRun) RuntimeT {
+	return runtimeStats.AddCoSchedCATRuntime(application, coSchedApplication, CATMask, data)
 }
@@ -0,0 +1,61 @@
+package stats
+
+// CgroupLimitsT are cgroup v2 limits by interface file, e.g. "memory.max": "512M"
+type CgroupLimitsT map[string]string
+
+// CgroupSettingT is a named setting of the limits of the cgroups of the 1st and 2nd command
+type CgroupSettingT struct {
+	Name   string
+	Limits [2]CgroupLimitsT
+}
+
+// PressureT is a line of a PSI pressure file
+type PressureT struct {
+	Avg10  float64
+	Avg60  float64
+	Avg300 float64
+	// total stall time in microseconds
+	Total uint64
+}
+
+// CgroupStatsT is the resource usage of a run reported by its cgroup. Files the kernel does not provide are missing.
+type CgroupStatsT struct {
+	// maximum memory usage in bytes from memory.peak, 0 if unknown
+	MemoryPeak uint64
+	// cpu.stat by key, e.g. usage_usec or throttled_usec
+	CPUStat map[string]uint64 `json:",omitempty"`
+	// PSI by resource (cpu, memory, io) and line (some, full)
+	Pressure map[string]map[string]PressureT `json:",omitempty"`
+}
+
+// SetCgroups stores the cgroup root and the swept cgroup settings in the config struct
+func (s *StatsT) SetCgroups(root string, settings []CgroupSettingT) {
+	s.Commandline.CgroupRoot = root
+	s.Commandline.Cgroups = settings
+}
+
+// AddCgroupRuntime adds the individual runtime with the cgroup setting
+func (s *StatsT) AddCgroupRuntime(application string, setting string, data []DataPerRun) RuntimeT {
+	return s.addRuntime(ConfigT{App: application, Cgroup: setting}, NoCATMask, data)
+}
+
+// AddCoSchedCgroupRuntime adds the co-scheduling runtime of 'application' co-scheduled with coSchedApplication with the
+// cgroup setting
+func (s *StatsT) AddCoSchedCgroupRuntime(application string, coSchedApplication string, setting string, data []DataPerRun) RuntimeT {
+	return s.addRuntime(ConfigT{App: application, CoSched: coSchedApplication, Cgroup: setting}, NoCATMask, data)
+}
+
+// SetCgroups stores the cgroup root and the swept cgroup settings in the config struct of the stats of the package
+func SetCgroups(root string, settings []CgroupSettingT) {
+	runtimeStats.SetCgroups(root, settings)
+}
+
+// AddCgroupRuntime adds the individual runtime with the cgroup setting to the stats of the package
+func AddCgroupRuntime(application string, setting string, data []DataPerRun) RuntimeT {
+	return runtimeStats.AddCgroupRuntime(application, setting, data)
+}
+
+// AddCoSchedCgroupRuntime adds the co-scheduling runtime with the cgroup setting to the stats of the package
+func AddCoSchedCgroupRuntime(application string, coSchedApplication string, setting string, data []DataPerRun) RuntimeT {
+	return runtimeStats.AddCoSchedCgroupRuntime(application, coSchedApplication, setting, data)
+}
@@ -0,0 +1,133 @@
+package stats
+
+import (
+	"math"
+
+	"github.com/montanaflynn/stats"
+)
+
+// DefaultCILevel is the confidence level used if none is configured
+const DefaultCILevel = 0.95
+
+// ConfidenceInterval returns the confidence interval of the mean of values based on the Student's t-distribution.
+// relHalfWidth is the half width of the interval relative to the mean. ok is false if less than 2 values are provided.
+func ConfidenceInterval(values []float64, level float64) (ci [2]float64, relHalfWidth float64, ok bool) {
+	n := len(values)
+	if n < 2 {
+		return
+	}
+
+	mean, _ := stats.Mean(values)
+	sampleVari, _ := stats.SampleVariance(values)
+
+	halfWidth := StudentTQuantile(1-(1-level)/2, float64(n-1)) * math.Sqrt(sampleVari/float64(n))
+	ci = [2]float64{mean - halfWidth, mean + halfWidth}
+	relHalfWidth = math.Abs(halfWidth / mean)
+	ok = !math.IsNaN(relHalfWidth) && !math.IsInf(relHalfWidth, 0)
+
+	return
+}
+
+// StudentTCDF is the cumulative distribution function of the Student's t-distribution with df degrees of freedom
+func StudentTCDF(t float64, df float64) float64 {
+	x := df / (df + t*t)
+	p := 0.5 * regIncBeta(df/2, 0.5, x)
+	if t > 0 {
+		return 1 - p
+	}
+	return p
+}
+
+// StudentTQuantile is the inverse of StudentTCDF
+func StudentTQuantile(p float64, df float64) float64 {
+	if p <= 0 || p >= 1 || df <= 0 {
+		return math.NaN()
+	}
+
+	// bisection is precise enough for our use case
+	lo, hi := -1.0, 1.0
+	for StudentTCDF(lo, df) > p {
+		lo *= 2
+	}
+	for StudentTCDF(hi, df) < p {
+		hi *= 2
+	}
+	for i := 0; i < 200 && hi-lo > 1e-12; i++ {
+		mid := (lo + hi) / 2
+		if StudentTCDF(mid, df) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// regularized incomplete beta function I_x(a, b)
+func regIncBeta(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	front := math.Exp(lab - la - lb + a*math.Log(x) + b*math.Log(1-x))
+
+	// the continued fraction converges quickly for x < (a+1)/(a+b+2)
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(a, b, x) / a
+	}
+	return 1 - front*betaContinuedFraction(b, a, 1-x)/b
+}
+
+// Lentz's algorithm, see Numerical Recipes 6.4
+func betaContinuedFraction(a, b, x float64) float64 {
+	const tiny = 1e-300
+	const eps = 1e-15
+
+	c := 1.0
+	d := 1 - (a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+
+	for m := 1.0; m <= 300; m++ {
+		m2 := 2 * m
+
+		aa := m * (b - m) * x / ((a + m2 - 1) * (a + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+
+		aa = -(a + m) * (a + b + m) * x / ((a + m2) * (a + m2 + 1))
+		d = 1 + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+
+		if math.Abs(del-1) < eps {
+			break
+		}
+	}
+	return h
+}
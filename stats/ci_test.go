@@ -0,0 +1,37 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStudentTQuantile(t *testing.T) {
+	// values from a t-table
+	expected := []struct {
+		p, df, t float64
+	}{
+		{0.975, 1, 12.706},
+		{0.975, 10, 2.228},
+		{0.995, 5, 4.032},
+		{0.95, 30, 1.697},
+		{0.5, 7, 0},
+	}
+
+	for _, e := range expected {
+		if q := StudentTQuantile(e.p, e.df); math.Abs(q-e.t) > 1e-3 {
+			t.Errorf("StudentTQuantile(%v, %v) = %v, expected %v", e.p, e.df, q, e.t)
+		}
+	}
+}
+
+func TestConfidenceInterval(t *testing.T) {
+	if _, _, ok := ConfidenceInterval([]float64{1}, 0.95); ok {
+		t.Errorf("ConfidenceInterval of a single value must not be ok")
+	}
+
+	ci, rel, ok := ConfidenceInterval([]float64{9, 10, 11}, 0.95)
+	// 4.303 * 1 / sqrt(3)
+	if !ok || math.Abs(ci[0]-7.5156) > 1e-3 || math.Abs(ci[1]-12.4844) > 1e-3 || math.Abs(rel-0.24844) > 1e-4 {
+		t.Errorf("Unexpected confidence interval %v ±%v", ci, rel)
+	}
+}
@@ -0,0 +1,238 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/montanaflynn/stats"
+)
+
+// Tests used to compare two datasets
+const (
+	WelchTest       = "welch"
+	MannWhitneyTest = "mwu"
+)
+
+// CompareOptionsT configures the comparison of two datasets
+type CompareOptionsT struct {
+	// WelchTest or MannWhitneyTest
+	Test string
+	// significance level
+	Alpha float64
+	// relative increase of the mean runtime counted as a regression
+	Threshold float64
+}
+
+// ConfigMaskT is a configuration measured with a CAT mask. Configurations are identified by the number of CAT bits,
+// but masks with the same number of bits, e.g. 0x0f and 0xf0, can perform differently.
+type ConfigMaskT struct {
+	Config ConfigT
+	// NoCATMask without CAT
+	CATMask uint64
+}
+
+// ComparisonT is the comparison of a configuration and CAT mask measured in both datasets
+type ComparisonT struct {
+	ConfigMaskT
+	OldMean float64
+	NewMean float64
+	OldRuns int
+	NewRuns int
+	// (NewMean - OldMean) / OldMean
+	Change float64
+	P      float64
+	// Hedges' g for Welch's t-test, Cliff's delta for the Mann-Whitney U test. Positive if the new runs are slower.
+	EffectSize  float64
+	Significant bool
+	// significantly slower by more than the threshold
+	Regression bool
+}
+
+// Compare tests every configuration and CAT mask measured in both datasets for a change of the runtime
+func Compare(old *StatsT, new *StatsT, opts CompareOptionsT) ([]ComparisonT, error) {
+	if opts.Test != WelchTest && opts.Test != MannWhitneyTest {
+		return nil, fmt.Errorf("Unknown test %v", opts.Test)
+	}
+
+	var ret []ComparisonT
+	for _, cm := range configMasks(old) {
+		a := maskRuntimes(old, cm)
+		b := maskRuntimes(new, cm)
+		if len(a) < 2 || len(b) < 2 {
+			continue
+		}
+
+		cmp := ComparisonT{ConfigMaskT: cm, OldRuns: len(a), NewRuns: len(b)}
+		cmp.OldMean, _ = stats.Mean(a)
+		cmp.NewMean, _ = stats.Mean(b)
+		cmp.Change = (cmp.NewMean - cmp.OldMean) / cmp.OldMean
+
+		if opts.Test == WelchTest {
+			_, _, cmp.P = WelchTTest(a, b)
+			cmp.EffectSize = HedgesG(a, b)
+		} else {
+			_, cmp.P = MannWhitneyU(a, b)
+			cmp.EffectSize = CliffsDelta(a, b)
+		}
+
+		cmp.Significant = cmp.P < opts.Alpha
+		cmp.Regression = cmp.Significant && cmp.Change > opts.Threshold
+		ret = append(ret, cmp)
+	}
+
+	return ret, nil
+}
+
+// UnmatchedConfigs returns the configurations and CAT masks measured in a, but not in b
+func UnmatchedConfigs(a *StatsT, b *StatsT) []ConfigMaskT {
+	var ret []ConfigMaskT
+	for _, cm := range configMasks(a) {
+		if r := b.Runtime(cm.Config); r == nil || r.RawRuntimesByMask == nil {
+			ret = append(ret, cm)
+		} else if _, exists := (*r.RawRuntimesByMask)[cm.CATMask]; !exists {
+			ret = append(ret, cm)
+		}
+	}
+	return ret
+}
+
+// every configuration of the dataset with each of its CAT masks in ascending order
+func configMasks(s *StatsT) []ConfigMaskT {
+	var ret []ConfigMaskT
+	for _, c := range s.Configs() {
+		r := s.Runtime(c)
+		if r.RawRuntimesByMask == nil {
+			continue
+		}
+		masks := make([]uint64, 0, len(*r.RawRuntimesByMask))
+		for mask := range *r.RawRuntimesByMask {
+			masks = append(masks, mask)
+		}
+		sort.Slice(masks, func(i, j int) bool { return masks[i] < masks[j] })
+		for _, mask := range masks {
+			ret = append(ret, ConfigMaskT{c, mask})
+		}
+	}
+	return ret
+}
+
+// runtimes in seconds of the runs that are not excluded of the configuration with the CAT mask
+func maskRuntimes(s *StatsT, cm ConfigMaskT) []float64 {
+	r := s.Runtime(cm.Config)
+	if r == nil || r.RawRuntimesByMask == nil {
+		return nil
+	}
+	var ret []float64
+	for _, data := range (*r.RawRuntimesByMask)[cm.CATMask] {
+		if !data.Excluded() {
+			ret = append(ret, data.Runtime.Seconds())
+		}
+	}
+	return ret
+}
+
+// WelchTTest tests if a and b have the same mean without assuming equal variances. p is two-sided.
+func WelchTTest(a []float64, b []float64) (t float64, df float64, p float64) {
+	na, nb := float64(len(a)), float64(len(b))
+	ma, _ := stats.Mean(a)
+	mb, _ := stats.Mean(b)
+	va, _ := stats.SampleVariance(a)
+	vb, _ := stats.SampleVariance(b)
+
+	se2 := va/na + vb/nb
+	if se2 == 0 {
+		// no variance at all, the means either differ or not
+		if ma == mb {
+			return 0, na + nb - 2, 1
+		}
+		return math.Copysign(math.Inf(1), mb-ma), na + nb - 2, 0
+	}
+
+	t = (mb - ma) / math.Sqrt(se2)
+	df = se2 * se2 / ((va/na)*(va/na)/(na-1) + (vb/nb)*(vb/nb)/(nb-1))
+	p = 2 * (1 - StudentTCDF(math.Abs(t), df))
+	return
+}
+
+// MannWhitneyU tests if values of b tend to be larger or smaller than values of a. u is the statistic of a,
+// p is two-sided based on the normal approximation with tie and continuity correction.
+func MannWhitneyU(a []float64, b []float64) (u float64, p float64) {
+	type valueT struct {
+		v     float64
+		fromA bool
+	}
+	var all []valueT
+	for _, v := range a {
+		all = append(all, valueT{v, true})
+	}
+	for _, v := range b {
+		all = append(all, valueT{v, false})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].v < all[j].v })
+
+	// average ranks of ties
+	rankSumA, ties := 0.0, 0.0
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].v == all[i].v {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if all[k].fromA {
+				rankSumA += rank
+			}
+		}
+		t := float64(j - i)
+		ties += t*t*t - t
+		i = j
+	}
+
+	na, nb := float64(len(a)), float64(len(b))
+	n := na + nb
+	u = rankSumA - na*(na+1)/2
+
+	sigma := math.Sqrt(na * nb / 12 * ((n + 1) - ties/(n*(n-1))))
+	if sigma == 0 {
+		return u, 1
+	}
+	z := math.Max(math.Abs(u-na*nb/2)-0.5, 0) / sigma
+	p = math.Erfc(z / math.Sqrt2)
+	return
+}
+
+// HedgesG is the difference of the means of b and a in pooled standard deviations corrected for small samples
+func HedgesG(a []float64, b []float64) float64 {
+	na, nb := float64(len(a)), float64(len(b))
+	ma, _ := stats.Mean(a)
+	mb, _ := stats.Mean(b)
+	va, _ := stats.SampleVariance(a)
+	vb, _ := stats.SampleVariance(b)
+
+	sp := math.Sqrt(((na-1)*va + (nb-1)*vb) / (na + nb - 2))
+	if sp == 0 {
+		return 0
+	}
+	return (mb - ma) / sp * (1 - 3/(4*(na+nb)-9))
+}
+
+// CliffsDelta is the probability that a value of b is larger than a value of a minus the reverse probability
+func CliffsDelta(a []float64, b []float64) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	sum := 0
+	for _, x := range a {
+		for _, y := range b {
+			switch {
+			case y > x:
+				sum++
+			case y < x:
+				sum--
+			}
+		}
+	}
+	return float64(sum) / float64(len(a)*len(b))
+}
@@ -0,0 +1,92 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWelchTTest(t *testing.T) {
+	tt, df, p := WelchTTest([]float64{1, 2, 3, 4, 5}, []float64{3, 4, 5, 6, 7})
+	// p from a t-table with t = 2 and 8 degrees of freedom
+	if tt != 2 || math.Abs(df-8) > 1e-9 || math.Abs(p-0.0805) > 1e-4 {
+		t.Errorf("Unexpected Welch's t-test %v %v %v", tt, df, p)
+	}
+
+	if _, _, p = WelchTTest([]float64{1, 1}, []float64{1, 1}); p != 1 {
+		t.Errorf("Identical constant samples must have p = 1, got %v", p)
+	}
+}
+
+func TestMannWhitneyU(t *testing.T) {
+	u, p := MannWhitneyU([]float64{1, 2, 3}, []float64{4, 5, 6})
+	if u != 0 || math.Abs(p-0.0809) > 1e-4 {
+		t.Errorf("Unexpected Mann-Whitney U test %v %v", u, p)
+	}
+
+	// ties get the average rank
+	if u, _ = MannWhitneyU([]float64{1, 2}, []float64{2, 3}); u != 0.5 {
+		t.Errorf("Unexpected U with ties %v", u)
+	}
+}
+
+func TestEffectSizes(t *testing.T) {
+	if d := CliffsDelta([]float64{1, 2, 3}, []float64{4, 5, 6}); d != 1 {
+		t.Errorf("CliffsDelta = %v, expected 1", d)
+	}
+	if d := CliffsDelta([]float64{1, 2}, []float64{2, 1}); d != 0 {
+		t.Errorf("CliffsDelta = %v, expected 0", d)
+	}
+	// pooled standard deviation 1.5811, correction 1 - 3/31
+	if g := HedgesG([]float64{1, 2, 3, 4, 5}, []float64{3, 4, 5, 6, 7}); math.Abs(g-2/math.Sqrt(2.5)*(1-3.0/31)) > 1e-9 {
+		t.Errorf("Unexpected HedgesG %v", g)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	newDataset := func(ref []float64, cat []float64) *StatsT {
+		catRuntimes := map[int]RuntimeT{4: newRuntimeT(0xf, secondsToRuns(cat...))}
+		return &StatsT{Runtimes: map[string]*RuntimePerAppT{
+			"app": {ReferenceRuntimes: newRuntimeT(NoCATMask, secondsToRuns(ref...)), CATRuntimes: &catRuntimes},
+		}}
+	}
+
+	old := newDataset([]float64{1, 1.01, 0.99, 1}, []float64{2, 2.01, 1.99, 2})
+	new := newDataset([]float64{1.2, 1.21, 1.19, 1.2}, []float64{2, 2.02, 1.98, 2})
+	new.Runtimes["other"] = &RuntimePerAppT{ReferenceRuntimes: newRuntimeT(NoCATMask, secondsToRuns(1, 1))}
+
+	for _, test := range []string{WelchTest, MannWhitneyTest} {
+		cmps, err := Compare(old, new, CompareOptionsT{Test: test, Alpha: 0.05, Threshold: 0.1})
+		if err != nil || len(cmps) != 2 {
+			t.Fatalf("Unexpected comparison %v %v", cmps, err)
+		}
+		if cmps[0].Config != (ConfigT{App: "app"}) || !cmps[0].Regression || cmps[0].EffectSize <= 0 || math.Abs(cmps[0].Change-0.2) > 1e-9 {
+			t.Errorf("%v: expected a regression of the reference run %+v", test, cmps[0])
+		}
+		if cmps[1].Config.CATBits != 4 || cmps[1].CATMask != 0xf || cmps[1].Significant || cmps[1].Regression {
+			t.Errorf("%v: expected no change of the CAT run %+v", test, cmps[1])
+		}
+	}
+
+	if u := UnmatchedConfigs(new, old); len(u) != 1 || u[0].Config.App != "other" {
+		t.Errorf("Unexpected unmatched configurations %v", u)
+	}
+
+	// masks with the same number of bits are compared separately
+	oldCAT := newRuntimeT(0xf0, secondsToRuns(3, 3.01, 2.99, 3))
+	oldCAT.update(0xf, secondsToRuns(2, 2.01, 1.99, 2), &runtimeStats.Commandline)
+	(*old.Runtimes["app"].CATRuntimes)[4] = oldCAT
+	cmps, err := Compare(old, new, CompareOptionsT{Test: WelchTest, Alpha: 0.05, Threshold: 0.1})
+	if err != nil || len(cmps) != 2 {
+		t.Fatalf("Unexpected comparison %v %v", cmps, err)
+	}
+	if cmps[1].CATMask != 0xf || cmps[1].Significant {
+		t.Errorf("Expected no change of the mask 0xf %+v", cmps[1])
+	}
+	if u := UnmatchedConfigs(old, new); len(u) != 1 || u[0] != (ConfigMaskT{ConfigT{App: "app", CATBits: 4}, 0xf0}) {
+		t.Errorf("Unexpected unmatched masks %v", u)
+	}
+
+	if _, err := Compare(old, new, CompareOptionsT{Test: "foo"}); err == nil {
+		t.Errorf("Unknown tests must fail")
+	}
+}
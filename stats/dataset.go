@@ -0,0 +1,210 @@
+package stats
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+)
+
+// ConfigT identifies a measured configuration of an application
+type ConfigT struct {
+	App string
+	// co-scheduled application, "" for individual runs
+	CoSched string
+	// number of bits set in the CAT mask, NoCATMask without CAT
+	CATBits int
+	// name of the cgroup setting, "" without cgroup limits. Cgroup settings are measured without CAT.
+	Cgroup string
+}
+
+// ReadDataset reads a result file stored by StoreToFile without changing the state of the package
+func ReadDataset(filename string) (*StatsT, error) {
+	if st := GetStorage(filename); st != nil {
+		path, id, err := SplitLocation(filename)
+		if err != nil {
+			return nil, err
+		}
+		return st.Read(path, id)
+	}
+
+	raw, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret StatsT
+	if err = json.Unmarshal(raw, &ret); err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// Configs returns every measured configuration of the dataset sorted by application, co-scheduled application and CAT bits
+func (s *StatsT) Configs() []ConfigT {
+	var ret []ConfigT
+	for app, r := range s.Runtimes {
+		if r.ReferenceRuntimes.RawRuntimesByMask != nil {
+			ret = append(ret, ConfigT{App: app, CATBits: NoCATMask})
+		}
+		if r.CATRuntimes != nil {
+			for bits := range *r.CATRuntimes {
+				ret = append(ret, ConfigT{App: app, CATBits: bits})
+			}
+		}
+		if r.CoSchedRuntimes != nil {
+			for cosched := range *r.CoSchedRuntimes {
+				ret = append(ret, ConfigT{App: app, CoSched: cosched, CATBits: NoCATMask})
+			}
+		}
+		if r.CoSchedCATRuntimes != nil {
+			for cosched, rs := range *r.CoSchedCATRuntimes {
+				for bits := range rs {
+					ret = append(ret, ConfigT{App: app, CoSched: cosched, CATBits: bits})
+				}
+			}
+		}
+		if r.CgroupRuntimes != nil {
+			for setting := range *r.CgroupRuntimes {
+				ret = append(ret, ConfigT{App: app, CATBits: NoCATMask, Cgroup: setting})
+			}
+		}
+		if r.CoSchedCgroupRuntimes != nil {
+			for cosched, rs := range *r.CoSchedCgroupRuntimes {
+				for setting := range rs {
+					ret = append(ret, ConfigT{App: app, CoSched: cosched, CATBits: NoCATMask, Cgroup: setting})
+				}
+			}
+		}
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].App != ret[j].App {
+			return ret[i].App < ret[j].App
+		}
+		if ret[i].CoSched != ret[j].CoSched {
+			return ret[i].CoSched < ret[j].CoSched
+		}
+		if ret[i].CATBits != ret[j].CATBits {
+			return ret[i].CATBits < ret[j].CATBits
+		}
+		return ret[i].Cgroup < ret[j].Cgroup
+	})
+
+	return ret
+}
+
+// Runtime returns the runtime of the configuration or nil if it was not measured
+func (s *StatsT) Runtime(c ConfigT) *RuntimeT {
+	r, exists := s.Runtimes[c.App]
+	if !exists {
+		return nil
+	}
+
+	if c.Cgroup != "" {
+		var rs map[string]RuntimeT
+		if c.CoSched == "" && r.CgroupRuntimes != nil {
+			rs = *r.CgroupRuntimes
+		} else if c.CoSched != "" && r.CoSchedCgroupRuntimes != nil {
+			rs = (*r.CoSchedCgroupRuntimes)[c.CoSched]
+		}
+		ret, exists := rs[c.Cgroup]
+		if !exists {
+			return nil
+		}
+		return &ret
+	}
+
+	var rs map[int]RuntimeT
+	switch {
+	case c.CoSched == "" && c.CATBits == NoCATMask:
+		return &r.ReferenceRuntimes
+	case c.CoSched == "":
+		if r.CATRuntimes == nil {
+			return nil
+		}
+		rs = *r.CATRuntimes
+	case c.CATBits == NoCATMask:
+		if r.CoSchedRuntimes == nil {
+			return nil
+		}
+		ret, exists := (*r.CoSchedRuntimes)[c.CoSched]
+		if !exists {
+			return nil
+		}
+		return &ret
+	default:
+		if r.CoSchedCATRuntimes == nil {
+			return nil
+		}
+		rs = (*r.CoSchedCATRuntimes)[c.CoSched]
+	}
+
+	ret, exists := rs[c.CATBits]
+	if !exists {
+		return nil
+	}
+	return &ret
+}
+
+// SetRuntime stores the runtime of the configuration, replacing a stored one
+func (s *StatsT) SetRuntime(c ConfigT, r RuntimeT) {
+	if s.Runtimes == nil {
+		s.Runtimes = make(map[string]*RuntimePerAppT)
+	}
+	rt, exists := s.Runtimes[c.App]
+	if !exists {
+		rt = &RuntimePerAppT{}
+		s.Runtimes[c.App] = rt
+	}
+
+	switch {
+	case c.Cgroup != "" && c.CoSched == "":
+		if rt.CgroupRuntimes == nil {
+			temp := make(map[string]RuntimeT)
+			rt.CgroupRuntimes = &temp
+		}
+		(*rt.CgroupRuntimes)[c.Cgroup] = r
+	case c.Cgroup != "":
+		if rt.CoSchedCgroupRuntimes == nil {
+			temp := make(map[string]map[string]RuntimeT)
+			rt.CoSchedCgroupRuntimes = &temp
+		}
+		if (*rt.CoSchedCgroupRuntimes)[c.CoSched] == nil {
+			(*rt.CoSchedCgroupRuntimes)[c.CoSched] = make(map[string]RuntimeT)
+		}
+		(*rt.CoSchedCgroupRuntimes)[c.CoSched][c.Cgroup] = r
+	case c.CoSched == "" && c.CATBits == NoCATMask:
+		rt.ReferenceRuntimes = r
+	case c.CoSched == "":
+		if rt.CATRuntimes == nil {
+			temp := make(map[int]RuntimeT)
+			rt.CATRuntimes = &temp
+		}
+		(*rt.CATRuntimes)[c.CATBits] = r
+	case c.CATBits == NoCATMask:
+		if rt.CoSchedRuntimes == nil {
+			temp := make(map[string]RuntimeT)
+			rt.CoSchedRuntimes = &temp
+		}
+		(*rt.CoSchedRuntimes)[c.CoSched] = r
+	default:
+		if rt.CoSchedCATRuntimes == nil {
+			temp := make(map[string]map[int]RuntimeT)
+			rt.CoSchedCATRuntimes = &temp
+		}
+		if (*rt.CoSchedCATRuntimes)[c.CoSched] == nil {
+			(*rt.CoSchedCATRuntimes)[c.CoSched] = make(map[int]RuntimeT)
+		}
+		(*rt.CoSchedCATRuntimes)[c.CoSched][c.CATBits] = r
+	}
+}
+
+// GetConfigs returns every configuration currently stored
+func GetConfigs() []ConfigT {
+	return runtimeStats.Configs()
+}
+
+// GetConfigRuntime returns the runtime of a configuration currently stored or nil if it was not measured
+func GetConfigRuntime(c ConfigT) *RuntimeT {
+	return runtimeStats.Runtime(c)
+}
@@ -0,0 +1,287 @@
+package stats
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jbreitbart/coBench/commands"
+	mstats "github.com/montanaflynn/stats"
+)
+
+// columns describing the configuration, shared by both exports
+var exportConfigColumns = []string{"app", "corunner", "cat_bits", "cgroup", "cpus"}
+
+// rusage columns of the run export, the config export contains their mean
+var exportRusageColumns = []string{"utime_s", "stime_s", "maxrss_kb", "minflt", "majflt", "nvcsw", "nivcsw"}
+
+// cgroup columns of the run export: memory.peak, cpu.stat and the PSI stall times of the run
+var exportCgroupColumns = []string{"cgroup_memory_peak_bytes", "cgroup_cpu_usage_us", "cgroup_cpu_throttled_us", "cgroup_cpu_some_us",
+	"cgroup_memory_some_us", "cgroup_memory_full_us", "cgroup_io_some_us", "cgroup_io_full_us"}
+
+// ExportRuns writes every run of every configuration as one row with the given separator, e.g. ',' or '\t'.
+// Warm-up runs and outliers are included and marked. Application metrics and perf counters are stored in one
+// column each, prefixed by metric_ and perf_. Values a run does not have are empty.
+func ExportRuns(w io.Writer, comma rune) error {
+	return runtimeStats.ExportRuns(w, comma)
+}
+
+// ExportRuns writes every run of every configuration of the stats, see ExportRuns
+func (s *StatsT) ExportRuns(w io.Writer, comma rune) error {
+	metrics, perfs := s.exportNames()
+
+	header := append([]string{}, exportConfigColumns...)
+	header = append(header, "cat_mask", "run", "warmup", "outlier", "start", "runtime_s")
+	header = append(header, exportRusageColumns...)
+	header = append(header, exportCgroupColumns...)
+	header = append(header, prefixed("metric_", metrics)...)
+	header = append(header, prefixed("perf_", perfs)...)
+
+	out := csv.NewWriter(w)
+	out.Comma = comma
+	if err := out.Write(header); err != nil {
+		return err
+	}
+
+	for _, c := range s.Configs() {
+		r := s.Runtime(c)
+		masks := make([]uint64, 0, len(*r.RawRuntimesByMask))
+		for mask := range *r.RawRuntimesByMask {
+			masks = append(masks, mask)
+		}
+		sort.Slice(masks, func(i, j int) bool { return masks[i] < masks[j] })
+
+		run := 0
+		for _, mask := range masks {
+			for _, data := range (*r.RawRuntimesByMask)[mask] {
+				row := s.exportConfig(c)
+				row = append(row, fmt.Sprintf("%x", mask), strconv.Itoa(run), strconv.FormatBool(data.Warmup), strconv.FormatBool(data.Outlier),
+					data.Start.Format(time.RFC3339Nano), formatFloat(data.Runtime.Seconds()))
+				row = append(row, rusageToStrings(data.Rusage)...)
+				row = append(row, cgroupToStrings(data.Cgroup)...)
+
+				for _, m := range metrics {
+					row = append(row, optionalFloat(data.Metrics, m))
+				}
+				counters := ParsePerfCounters(data.Output)
+				for _, p := range perfs {
+					row = append(row, optionalFloat(counters, p))
+				}
+
+				if err := out.Write(row); err != nil {
+					return err
+				}
+				run++
+			}
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// ExportConfigs writes one row per configuration with the statistics of the runs used, i.e. without warm-up runs and
+// outliers. Application metrics and perf counters have a mean and a standard deviation column each.
+func ExportConfigs(w io.Writer, comma rune) error {
+	return runtimeStats.ExportConfigs(w, comma)
+}
+
+// ExportConfigs writes one row per configuration of the stats, see ExportConfigs
+func (s *StatsT) ExportConfigs(w io.Writer, comma rune) error {
+	metrics, perfs := s.exportNames()
+
+	header := append([]string{}, exportConfigColumns...)
+	header = append(header, "runs", "outliers", "runtime_mean_s", "runtime_stddev_s", "runtime_median_s", "runtime_p25_s",
+		"runtime_p75_s", "runtime_ci_low_s", "runtime_ci_high_s", "ci_level")
+	header = append(header, prefixed("mean_", exportRusageColumns)...)
+	for _, m := range metrics {
+		header = append(header, "metric_"+m+"_mean", "metric_"+m+"_stddev")
+	}
+	for _, p := range perfs {
+		header = append(header, "perf_"+p+"_mean", "perf_"+p+"_stddev")
+	}
+
+	out := csv.NewWriter(w)
+	out.Comma = comma
+	if err := out.Write(header); err != nil {
+		return err
+	}
+
+	for _, c := range s.Configs() {
+		r := s.Runtime(c)
+
+		row := s.exportConfig(c)
+		row = append(row, strconv.Itoa(r.Runs), strconv.Itoa(r.Outliers))
+		for _, v := range []float64{r.Mean, r.Stddev, r.Median, r.P25, r.P75, r.CI[0], r.CI[1], r.CILevel} {
+			row = append(row, formatFloat(v))
+		}
+
+		var rusages [][]float64
+		perfValues := make(map[string][]float64)
+		for _, data := range *r.RawRuntimesByMask {
+			for _, run := range data {
+				if run.Excluded() {
+					continue
+				}
+				if run.Rusage != nil {
+					rusages = append(rusages, run.Rusage.values())
+				}
+				for name, v := range ParsePerfCounters(run.Output) {
+					perfValues[name] = append(perfValues[name], v)
+				}
+			}
+		}
+		for i := range exportRusageColumns {
+			var values []float64
+			for _, u := range rusages {
+				values = append(values, u[i])
+			}
+			row = append(row, optionalMean(values))
+		}
+
+		for _, m := range metrics {
+			values := r.MetricValues(m)
+			row = append(row, optionalMean(values), optionalStddev(values))
+		}
+		for _, p := range perfs {
+			row = append(row, optionalMean(perfValues[p]), optionalStddev(perfValues[p]))
+		}
+
+		if err := out.Write(row); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// sorted names of all application metrics and perf counters found in the runs
+func (s *StatsT) exportNames() (metrics []string, perfs []string) {
+	seenMetrics := make(map[string]bool)
+	seenPerfs := make(map[string]bool)
+	for _, c := range s.Configs() {
+		for _, data := range *s.Runtime(c).RawRuntimesByMask {
+			for _, run := range data {
+				for name := range run.Metrics {
+					seenMetrics[name] = true
+				}
+				for name := range ParsePerfCounters(run.Output) {
+					seenPerfs[name] = true
+				}
+			}
+		}
+	}
+
+	for name := range seenMetrics {
+		metrics = append(metrics, name)
+	}
+	for name := range seenPerfs {
+		perfs = append(perfs, name)
+	}
+	sort.Strings(metrics)
+	sort.Strings(perfs)
+	return
+}
+
+func (s *StatsT) exportConfig(c ConfigT) []string {
+	return []string{c.App, c.CoSched, strconv.Itoa(c.CATBits), c.Cgroup, s.cpuSet(c)}
+}
+
+// CPUs the application of the configuration was pinned to. Individual runs use the CPUs of the 1st command, in a pair
+// the 1st application of the pair as generated from the command list uses the CPUs of the 1st command.
+func (s *StatsT) cpuSet(c ConfigT) string {
+	if c.CoSched == "" || c.CoSched == c.App {
+		return s.Commandline.CPUs[0]
+	}
+	for _, pair := range commands.GeneratePairs(s.Commandline.Commands) {
+		if pair[0] == c.CoSched && pair[1] == c.App {
+			return s.Commandline.CPUs[1]
+		}
+		if pair[0] == c.App && pair[1] == c.CoSched {
+			break
+		}
+	}
+	return s.Commandline.CPUs[0]
+}
+
+func prefixed(prefix string, names []string) []string {
+	var ret []string
+	for _, n := range names {
+		ret = append(ret, prefix+n)
+	}
+	return ret
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func optionalFloat(values map[string]float64, name string) string {
+	v, exists := values[name]
+	if !exists {
+		return ""
+	}
+	return formatFloat(v)
+}
+
+func optionalMean(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	mean, _ := mstats.Mean(values)
+	return formatFloat(mean)
+}
+
+func optionalStddev(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	stddev, _ := mstats.StandardDeviation(values)
+	return formatFloat(stddev)
+}
+
+// in the order of exportRusageColumns
+func (u *RusageT) values() []float64 {
+	return []float64{u.UserTime.Seconds(), u.SystemTime.Seconds(), float64(u.MaxRSS), float64(u.MinorFaults), float64(u.MajorFaults),
+		float64(u.VoluntaryContextSwitches), float64(u.InvoluntaryContextSwitches)}
+}
+
+func rusageToStrings(u *RusageT) []string {
+	ret := make([]string, len(exportRusageColumns))
+	if u == nil {
+		return ret
+	}
+	for i, v := range u.values() {
+		ret[i] = formatFloat(v)
+	}
+	return ret
+}
+
+// in the order of exportCgroupColumns, values the kernel did not report are empty
+func cgroupToStrings(c *CgroupStatsT) []string {
+	ret := make([]string, len(exportCgroupColumns))
+	if c == nil {
+		return ret
+	}
+	if c.MemoryPeak != 0 {
+		ret[0] = strconv.FormatUint(c.MemoryPeak, 10)
+	}
+	optional := func(i int, v uint64, exists bool) {
+		if exists {
+			ret[i] = strconv.FormatUint(v, 10)
+		}
+	}
+	usage, exists := c.CPUStat["usage_usec"]
+	optional(1, usage, exists)
+	throttled, exists := c.CPUStat["throttled_usec"]
+	optional(2, throttled, exists)
+	for i, p := range [][2]string{{"cpu", "some"}, {"memory", "some"}, {"memory", "full"}, {"io", "some"}, {"io", "full"}} {
+		psi, exists := c.Pressure[p[0]][p[1]]
+		optional(3+i, psi.Total, exists)
+	}
+	return ret
+}
@@ -0,0 +1,95 @@
+package stats
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+)
+
+func TestExport(t *testing.T) {
+	saved := runtimeStats
+	defer func() { runtimeStats = saved }()
+
+	runs := secondsToRuns(1, 2, 3)
+	runs[0].Output = " Performance counter stats for 'b':\n\n   1000       LLC-load-misses\n\n"
+	runs[0].Metrics = map[string]float64{"throughput": 5}
+	runs[0].Rusage = &RusageT{UserTime: 500 * time.Millisecond, MaxRSS: 1024}
+	runs[2].Warmup = true
+
+	coSched := map[string]RuntimeT{"a": newRuntimeT(NoCATMask, secondsToRuns(4))}
+	limited := secondsToRuns(5)
+	limited[0].Cgroup = &CgroupStatsT{MemoryPeak: 4096, CPUStat: map[string]uint64{"usage_usec": 900},
+		Pressure: map[string]map[string]PressureT{"memory": {"some": {Total: 20}, "full": {Total: 10}}}}
+	cgroups := map[string]RuntimeT{"mem": newRuntimeT(NoCATMask, limited)}
+	runtimeStats = StatsT{
+		Commandline: CommandlineT{Commands: []string{"a", "b"}, CPUs: [2]string{"0-3", "4-7"}},
+		Runtimes: map[string]*RuntimePerAppT{
+			"a": {ReferenceRuntimes: newRuntimeT(NoCATMask, secondsToRuns(2))},
+			"b": {ReferenceRuntimes: newRuntimeT(NoCATMask, runs), CoSchedRuntimes: &coSched, CgroupRuntimes: &cgroups},
+		},
+	}
+
+	var b bytes.Buffer
+	if err := ExportRuns(&b, '\t'); err != nil {
+		t.Fatal(err)
+	}
+	r := csv.NewReader(&b)
+	r.Comma = '\t'
+	rows, err := r.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// header, a, 3 runs of b, b with the cgroup setting, b co-scheduled with a
+	if len(rows) != 7 {
+		t.Fatalf("Expected 7 rows, got %v", rows)
+	}
+	header := make(map[string]int)
+	for i, name := range rows[0] {
+		header[name] = i
+	}
+	expected := map[string]string{"app": "b", "corunner": "", "cpus": "0-3", "cat_mask": "0", "run": "0", "runtime_s": "1",
+		"utime_s": "0.5", "maxrss_kb": "1024", "metric_throughput": "5", "perf_LLC-load-misses": "1000"}
+	for column, value := range expected {
+		if i, exists := header[column]; !exists || rows[2][i] != value {
+			t.Errorf("Expected %v = %q in %v", column, value, rows[2])
+		}
+	}
+	if row := rows[4]; row[header["warmup"]] != "true" || row[header["utime_s"]] != "" {
+		t.Errorf("Unexpected warm-up row %v", row)
+	}
+	expected = map[string]string{"cgroup": "mem", "runtime_s": "5", "cgroup_memory_peak_bytes": "4096", "cgroup_cpu_usage_us": "900",
+		"cgroup_cpu_throttled_us": "", "cgroup_memory_some_us": "20", "cgroup_memory_full_us": "10", "cgroup_io_some_us": ""}
+	for column, value := range expected {
+		if i, exists := header[column]; !exists || rows[5][i] != value {
+			t.Errorf("Expected %v = %q in %v", column, value, rows[5])
+		}
+	}
+	// b was the 2nd command of the pair
+	if row := rows[6]; row[header["corunner"]] != "a" || row[header["cpus"]] != "4-7" {
+		t.Errorf("Unexpected co-scheduling row %v", row)
+	}
+
+	b.Reset()
+	if err := ExportConfigs(&b, ','); err != nil {
+		t.Fatal(err)
+	}
+	rows, err = csv.NewReader(&b).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 5 {
+		t.Fatalf("Expected 5 rows, got %v", rows)
+	}
+	header = make(map[string]int)
+	for i, name := range rows[0] {
+		header[name] = i
+	}
+	expected = map[string]string{"app": "b", "runs": "2", "runtime_mean_s": "1.5", "mean_utime_s": "0.5", "metric_throughput_mean": "5",
+		"perf_LLC-load-misses_mean": "1000"}
+	for column, value := range expected {
+		if i, exists := header[column]; !exists || rows[2][i] != value {
+			t.Errorf("Expected %v = %q in %v", column, value, rows[2])
+		}
+	}
+}
@@ -4,9 +4,24 @@ import (
 	"io/ioutil"
 )
 
-// StoreToFile stores the current stats as json in a file
+// StoreToFile stores the current stats as json in a file or as a new campaign if a storage is registered for the file
+// extension
 func StoreToFile(filename string) error {
-	json, err := CreateJSON()
+	return runtimeStats.StoreToFile(filename)
+}
+
+// StoreToFile stores the stats as json in a file or as a new campaign if a storage is registered for the file extension
+func (s *StatsT) StoreToFile(filename string) error {
+	if st := GetStorage(filename); st != nil {
+		path, _, err := SplitLocation(filename)
+		if err != nil {
+			return err
+		}
+		_, err = st.Store(path, "", s)
+		return err
+	}
+
+	json, err := s.CreateJSON()
 	if err != nil {
 		return err
 	}
@@ -15,8 +30,17 @@ func StoreToFile(filename string) error {
 	return err
 }
 
-// ReadFromFile reads a json file stored by StoreToFile and updates the local state of the package
+// ReadFromFile reads a file stored by StoreToFile and updates the local state of the package
 func ReadFromFile(filename string) error {
+	if GetStorage(filename) != nil {
+		d, err := ReadDataset(filename)
+		if err != nil {
+			return err
+		}
+		runtimeStats = *d
+		return nil
+	}
+
 	raw, err := ioutil.ReadFile(filename)
 	if err != nil {
 		return err
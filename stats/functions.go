@@ -8,13 +8,14 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// CreateJSON creates a JSON representation of the stats
+func (s *StatsT) CreateJSON() ([]byte, error) {
+	return json.Marshal(s)
+}
+
 // CreateJSON creates a JSON representation of the current state
 func CreateJSON() ([]byte, error) {
-	json, err := json.Marshal(runtimeStats)
-	if err != nil {
-		return nil, err
-	}
-	return json, err
+	return runtimeStats.CreateJSON()
 }
 
 // StoreJSON parses the JSON and stores it in the state
@@ -24,11 +25,12 @@ func StoreJSON(raw []byte) error {
 
 func newRuntimeT(CATMask uint64, rawRuntimes []DataPerRun) RuntimeT {
 	var ret RuntimeT
-	ret.update(CATMask, rawRuntimes)
+	ret.update(CATMask, rawRuntimes, &runtimeStats.Commandline)
 	return ret
 }
 
-func (run *RuntimeT) update(CATMask uint64, data []DataPerRun) {
+// adds the runs and updates the statistics with the outlier detection and the confidence level of the command line
+func (run *RuntimeT) update(CATMask uint64, data []DataPerRun, cl *CommandlineT) {
 	if run.RawRuntimesByMask == nil {
 		temp := make(map[uint64][]DataPerRun, 1)
 		run.RawRuntimesByMask = &temp
@@ -40,11 +42,29 @@ func (run *RuntimeT) update(CATMask uint64, data []DataPerRun) {
 		(*run.RawRuntimesByMask)[CATMask] = data
 	}
 
-	var runtimeSeconds []float64
+	// outliers are detected on all measured runs, so the flags of old runs may change
+	var measured []*DataPerRun
+	var measuredSeconds []float64
 	for _, v := range *run.RawRuntimesByMask {
-		for _, r := range v {
-			runtimeSeconds = append(runtimeSeconds, r.Runtime.Seconds())
+		for i := range v {
+			if v[i].Warmup {
+				continue
+			}
+			measured = append(measured, &v[i])
+			measuredSeconds = append(measuredSeconds, v[i].Runtime.Seconds())
+		}
+	}
+
+	outliers := detectOutliers(measuredSeconds, cl.OutlierMethod, cl.OutlierThreshold)
+	var runtimeSeconds []float64
+	run.Outliers = 0
+	for i, r := range measured {
+		r.Outlier = outliers[i]
+		if r.Outlier {
+			run.Outliers++
+			continue
 		}
+		runtimeSeconds = append(runtimeSeconds, r.Runtime.Seconds())
 	}
 
 	var err error
@@ -66,6 +86,17 @@ func (run *RuntimeT) update(CATMask uint64, data []DataPerRun) {
 	}
 
 	run.Runs = len(runtimeSeconds)
+
+	level := cl.CILevel
+	if level == 0 {
+		level = DefaultCILevel
+	}
+	run.CILevel, run.CI, run.CIRelHalfWidth = 0, [2]float64{}, 0
+	if ci, rel, ok := ConfidenceInterval(runtimeSeconds, level); ok {
+		run.CILevel, run.CI, run.CIRelHalfWidth = level, ci, rel
+	}
+
+	run.updateRobust(runtimeSeconds, level)
 }
 
 // GetAllApplications returns a string slice containing all applications that are currently stored
@@ -73,19 +104,56 @@ func GetAllApplications() []string {
 	return runtimeStats.Commandline.Commands
 }
 
+// GetCommandline returns the command line options of the benchmark run
+func GetCommandline() CommandlineT {
+	return runtimeStats.Commandline
+}
+
 // SetCommandline stores the command line options in the config struct
-func SetCommandline(cat bool, catBitChunk uint64, catDirs []string, cpus [2]string, commands []string, hermitcore bool, resctrlPath string, runs int, threads string, varianceDiff float64) {
-	runtimeStats.Commandline.CAT = cat
-	runtimeStats.Commandline.CATChunk = catBitChunk
-	runtimeStats.Commandline.CATDirs = catDirs
-	runtimeStats.Commandline.CPUs = cpus
-	runtimeStats.Commandline.Commands = commands
-	runtimeStats.Commandline.HermitCore = hermitcore
-	runtimeStats.Commandline.ResctrlPath = resctrlPath
-	runtimeStats.Commandline.Runs = runs
-	runtimeStats.Commandline.Threads = threads
-	runtimeStats.Commandline.VarianceDiff = varianceDiff
+func (s *StatsT) SetCommandline(cat bool, catBitChunk uint64, catDirs []string, cpus [2]string, commands []string, hermitcore bool, resctrlPath string, runs int, threads string, varianceDiff float64) {
+	s.Commandline.CAT = cat
+	s.Commandline.CATChunk = catBitChunk
+	s.Commandline.CATDirs = catDirs
+	s.Commandline.CPUs = cpus
+	s.Commandline.Commands = commands
+	s.Commandline.HermitCore = hermitcore
+	s.Commandline.ResctrlPath = resctrlPath
+	s.Commandline.Runs = runs
+	s.Commandline.Threads = threads
+	s.Commandline.VarianceDiff = varianceDiff
 	if math.IsNaN(varianceDiff) {
-		runtimeStats.Commandline.VarianceDiff = -1.0
+		s.Commandline.VarianceDiff = -1.0
+	}
+}
+
+// SetLaunchers stores the launcher specifications in the config struct
+func (s *StatsT) SetLaunchers(launcher string, launchers map[string]string) {
+	s.Commandline.Launcher = launcher
+	s.Commandline.Launchers = launchers
+}
+
+// SetStoppingCriterion stores the options deciding how often an application is executed in the config struct
+func (s *StatsT) SetStoppingCriterion(ciWidth float64, ciLevel float64, maxRuns int, warmupRuns int) {
+	s.Commandline.CIWidth = ciWidth
+	if math.IsNaN(ciWidth) {
+		s.Commandline.CIWidth = -1.0
 	}
+	s.Commandline.CILevel = ciLevel
+	s.Commandline.MaxRuns = maxRuns
+	s.Commandline.WarmupRuns = warmupRuns
+}
+
+// SetCommandline stores the command line options in the config struct of the stats of the package
+func SetCommandline(cat bool, catBitChunk uint64, catDirs []string, cpus [2]string, commands []string, hermitcore bool, resctrlPath string, runs int, threads string, varianceDiff float64) {
+	runtimeStats.SetCommandline(cat, catBitChunk, catDirs, cpus, commands, hermitcore, resctrlPath, runs, threads, varianceDiff)
+}
+
+// SetLaunchers stores the launcher specifications in the config struct of the stats of the package
+func SetLaunchers(launcher string, launchers map[string]string) {
+	runtimeStats.SetLaunchers(launcher, launchers)
+}
+
+// SetStoppingCriterion stores the stopping criterion in the config struct of the stats of the package
+func SetStoppingCriterion(ciWidth float64, ciLevel float64, maxRuns int, warmupRuns int) {
+	runtimeStats.SetStoppingCriterion(ciWidth, ciLevel, maxRuns, warmupRuns)
 }
@@ -1,10 +1,8 @@
 package stats
 
-import "time"
-
-// GetCoSchedCATRuntimes returns the runtime of application when running in parallel to cosched with CAT
-func GetCoSchedCATRuntimes(application string, cosched string) *map[int]RuntimeT {
-	temp, exists := runtimeStats.Runtimes[application]
+// CoSchedCATRuntimes returns the runtime of application when running in parallel to cosched with CAT
+func (s *StatsT) CoSchedCATRuntimes(application string, cosched string) *map[int]RuntimeT {
+	temp, exists := s.Runtimes[application]
 	if !exists {
 		return nil
 	}
@@ -22,25 +20,23 @@ func GetCoSchedCATRuntimes(application string, cosched string) *map[int]RuntimeT
 }
 
 // GetCoSchedCATRuntimes returns the runtime of application when running in parallel to cosched with CAT
-func GetCoSchedCATRuntimesNormalized(application string, cosched string) *map[int]RuntimeT {
-	ref := GetReferenceRuntime(application)
-	cat := GetCoSchedCATRuntimes(application, cosched)
-	if ref == nil || cat == nil {
-		return nil
-	}
+func GetCoSchedCATRuntimes(application string, cosched string) *map[int]RuntimeT {
+	return runtimeStats.CoSchedCATRuntimes(application, cosched)
+}
 
-	meanInNanoseconds := int64(ref.Mean * 1e+9)
-	ret := make(map[int]RuntimeT)
-	for catKey, catR := range *cat {
-		ret[catKey] = normalizeRuntimeT(catR, meanInNanoseconds)
-	}
+// CoSchedCATRuntimesNormalized returns the slowdown of application when running in parallel to cosched with CAT compared to baseline
+func (s *StatsT) CoSchedCATRuntimesNormalized(application string, cosched string, baseline BaselineT, metric string) *map[int]NormalizedT {
+	return s.normalizeMap(application, s.CoSchedCATRuntimes(application, cosched), baseline, metric)
+}
 
-	return &ret
+// GetCoSchedCATRuntimesNormalized returns the slowdown of application when running in parallel to cosched with CAT compared to baseline
+func GetCoSchedCATRuntimesNormalized(application string, cosched string, baseline BaselineT, metric string) *map[int]NormalizedT {
+	return runtimeStats.CoSchedCATRuntimesNormalized(application, cosched, baseline, metric)
 }
 
-// GetCoSchedRuntimes returns the runtime of application when running in parallel to cosched without CAT
-func GetCoSchedRuntimes(application string, cosched string) *RuntimeT {
-	temp, exists := runtimeStats.Runtimes[application]
+// CoSchedRuntimes returns the runtime of application when running in parallel to cosched without CAT
+func (s *StatsT) CoSchedRuntimes(application string, cosched string) *RuntimeT {
+	temp, exists := s.Runtimes[application]
 	if !exists {
 		return nil
 	}
@@ -57,76 +53,65 @@ func GetCoSchedRuntimes(application string, cosched string) *RuntimeT {
 	return &ret
 }
 
-// GetCoSchedRuntimesNormalized returns the normalized runtime of application when running in parallel to cosched without CAT
-func GetCoSchedRuntimesNormalized(application string, cosched string) *RuntimeT {
-	ref := GetReferenceRuntime(application)
-	co := GetCoSchedRuntimes(application, cosched)
-	if ref == nil || co == nil {
-		return nil
-	}
+// GetCoSchedRuntimes returns the runtime of application when running in parallel to cosched without CAT
+func GetCoSchedRuntimes(application string, cosched string) *RuntimeT {
+	return runtimeStats.CoSchedRuntimes(application, cosched)
+}
 
-	meanInNanoseconds := int64(ref.Mean * 1e+9)
-	ret := normalizeRuntimeT(*co, meanInNanoseconds)
-	return &ret
+// CoSchedRuntimesNormalized returns the slowdown of application when running in parallel to cosched without CAT compared to baseline
+func (s *StatsT) CoSchedRuntimesNormalized(application string, cosched string, baseline BaselineT, metric string) *NormalizedT {
+	return s.Normalize(s.CoSchedRuntimes(application, cosched), s.Baseline(application, baseline, metric), metric)
 }
 
-// GetCATRuntimes returns all cat individual runtimes with CAT
-func GetCATRuntimes(application string) *map[int]RuntimeT {
-	_, exists := runtimeStats.Runtimes[application]
+// GetCoSchedRuntimesNormalized returns the slowdown of application when running in parallel to cosched without CAT compared to baseline
+func GetCoSchedRuntimesNormalized(application string, cosched string, baseline BaselineT, metric string) *NormalizedT {
+	return runtimeStats.CoSchedRuntimesNormalized(application, cosched, baseline, metric)
+}
+
+// CATRuntimes returns all cat individual runtimes with CAT
+func (s *StatsT) CATRuntimes(application string) *map[int]RuntimeT {
+	_, exists := s.Runtimes[application]
 	if exists {
-		return runtimeStats.Runtimes[application].CATRuntimes
+		return s.Runtimes[application].CATRuntimes
 	}
 	return nil
 }
 
-// GetCATRuntimesNormalized returns all cat individual runtimes with CAT normalized
-func GetCATRuntimesNormalized(application string) *map[int]RuntimeT {
-	ref := GetReferenceRuntime(application)
-	cat := GetCATRuntimes(application)
-	if ref == nil || cat == nil {
-		return nil
-	}
+// GetCATRuntimes returns all cat individual runtimes with CAT
+func GetCATRuntimes(application string) *map[int]RuntimeT {
+	return runtimeStats.CATRuntimes(application)
+}
 
-	meanInNanoseconds := int64(ref.Mean * 1e+9)
-	ret := make(map[int]RuntimeT)
-	for catKey, catR := range *cat {
-		ret[catKey] = normalizeRuntimeT(catR, meanInNanoseconds)
-	}
+// CATRuntimesNormalized returns the slowdown of all individual runtimes with CAT compared to baseline
+func (s *StatsT) CATRuntimesNormalized(application string, baseline BaselineT, metric string) *map[int]NormalizedT {
+	return s.normalizeMap(application, s.CATRuntimes(application), baseline, metric)
+}
 
-	return &ret
+// GetCATRuntimesNormalized returns the slowdown of all individual runtimes with CAT compared to baseline
+func GetCATRuntimesNormalized(application string, baseline BaselineT, metric string) *map[int]NormalizedT {
+	return runtimeStats.CATRuntimesNormalized(application, baseline, metric)
 }
 
-// GetReferenceRuntime returns the individual runtime without CAT
-func GetReferenceRuntime(application string) *RuntimeT {
-	_, exists := runtimeStats.Runtimes[application]
+// ReferenceRuntime returns the individual runtime without CAT
+func (s *StatsT) ReferenceRuntime(application string) *RuntimeT {
+	_, exists := s.Runtimes[application]
 	if exists {
-		return &runtimeStats.Runtimes[application].ReferenceRuntimes
+		return &s.Runtimes[application].ReferenceRuntimes
 	}
 	return nil
 }
 
-// GetReferenceRuntimeNormalized returns the individual runtime without CAT normalized
-func GetReferenceRuntimeNormalized(application string) *RuntimeT {
-	ref := GetReferenceRuntime(application)
-	if ref == nil {
-		return nil
-	}
-	meanInNanoseconds := int64(ref.Mean * 1e+9)
-	ret := normalizeRuntimeT(*ref, meanInNanoseconds)
-	return &ret
+// GetReferenceRuntime returns the individual runtime without CAT
+func GetReferenceRuntime(application string) *RuntimeT {
+	return runtimeStats.ReferenceRuntime(application)
 }
 
-func normalizeRuntimeT(ref RuntimeT, meanInNanoseconds int64) RuntimeT {
-	var rs []DataPerRun
-	for _, v := range *ref.RawRuntimesByMask {
-		for _, t := range v {
-			var r DataPerRun
-			r.Output = t.Output
-			r.Runtime = time.Duration(int64(t.Runtime) / meanInNanoseconds)
-			rs = append(rs, r)
-		}
-	}
+// ReferenceRuntimeNormalized returns the slowdown of the individual runtime without CAT compared to baseline
+func (s *StatsT) ReferenceRuntimeNormalized(application string, baseline BaselineT, metric string) *NormalizedT {
+	return s.Normalize(s.ReferenceRuntime(application), s.Baseline(application, baseline, metric), metric)
+}
 
-	ret := newRuntimeT(NoCATMask, rs)
-	return ret
+// GetReferenceRuntimeNormalized returns the slowdown of the individual runtime without CAT compared to baseline
+func GetReferenceRuntimeNormalized(application string, baseline BaselineT, metric string) *NormalizedT {
+	return runtimeStats.ReferenceRuntimeNormalized(application, baseline, metric)
 }
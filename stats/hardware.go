@@ -0,0 +1,53 @@
+package stats
+
+import (
+	"math"
+)
+
+// SetHardware stores the hardware description in the config struct
+func (s *StatsT) SetHardware(hw HardwareT) {
+	s.Hardware = hw
+}
+
+// SetHardware stores the hardware description in the config struct of the stats of the package
+func SetHardware(hw HardwareT) {
+	runtimeStats.SetHardware(hw)
+}
+
+// GetHardware returns the hardware the benchmarks were executed on
+func GetHardware() HardwareT {
+	return runtimeStats.Hardware
+}
+
+// MBPerCATBit returns the size of the L3 cache in MB assigned by one bit of the CAT capacity bitmask.
+// Returns NaN if the L3 size is unknown.
+func (hw HardwareT) MBPerCATBit() float64 {
+	bits := hw.CATBits
+	if bits == 0 {
+		// every bit of the capacity bitmask usually represents one way
+		bits = hw.L3Ways
+	}
+	if hw.L3Size == 0 || bits == 0 {
+		return math.NaN()
+	}
+	return float64(hw.L3Size) / (1024 * 1024) / float64(bits)
+}
+
+// WholeCacheBits returns the number of CAT bits of the whole cache. Without the hardware information it falls back to
+// the smallest plus the largest number of bits of the individual CAT runs in cat, which use min bits up to all bits
+// minus min bits. Returns 0 if neither is available.
+func (s *StatsT) WholeCacheBits(cat *map[int]RuntimeT) int {
+	if bits := s.Hardware.CATBits; bits != 0 {
+		return bits
+	}
+	if cat == nil || len(*cat) == 0 {
+		return 0
+	}
+	keys := SortedCATKeys(cat)
+	return keys[0] + keys[len(keys)-1]
+}
+
+// WholeCacheBits returns the number of CAT bits of the whole cache of the stats of the package
+func WholeCacheBits(cat *map[int]RuntimeT) int {
+	return runtimeStats.WholeCacheBits(cat)
+}
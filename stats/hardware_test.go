@@ -0,0 +1,28 @@
+package stats
+
+import (
+	"testing"
+)
+
+func TestWholeCacheBits(t *testing.T) {
+	var s StatsT
+	if bits := s.WholeCacheBits(nil); bits != 0 {
+		t.Errorf("WholeCacheBits(nil) = %v, expected 0", bits)
+	}
+	if bits := s.WholeCacheBits(&map[int]RuntimeT{}); bits != 0 {
+		t.Errorf("WholeCacheBits without CAT runs = %v, expected 0", bits)
+	}
+
+	// individual runs with 2 up to 6 of 8 bits
+	cat := map[int]RuntimeT{2: {}, 4: {}, 6: {}}
+	if bits := s.WholeCacheBits(&cat); bits != 8 {
+		t.Errorf("WholeCacheBits = %v, expected 8", bits)
+	}
+
+	s.SetHardware(HardwareT{CATBits: 11})
+	for _, c := range []*map[int]RuntimeT{nil, &cat} {
+		if bits := s.WholeCacheBits(c); bits != 11 {
+			t.Errorf("WholeCacheBits with hardware information = %v, expected 11", bits)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// SlowdownCellT is the slowdown of one application co-scheduled with another application
+type SlowdownCellT struct {
+	// false if no measurements exist
+	Valid    bool
+	Slowdown float64
+	// bootstrap confidence interval of the slowdown
+	CI [2]float64
+	// true if the confidence interval is not available or wider than requested
+	LowConfidence bool
+}
+
+// GetSlowdownMatrix returns the slowdown matrix of the stats of the package, see StatsT.SlowdownMatrix
+func GetSlowdownMatrix(apps []string, catBits int, metric string, level float64, maxRelCI float64) [][]SlowdownCellT {
+	return runtimeStats.SlowdownMatrix(apps, catBits, metric, level, maxRelCI)
+}
+
+// SlowdownMatrix returns the slowdown of the row application when co-scheduled with the column application.
+// With catBits > 0 the row application used catBits bits of the CAT mask, the column application the remaining ones.
+// Cells with a confidence interval wider than ±maxRelCI of the slowdown are marked as low confidence.
+func (s *StatsT) SlowdownMatrix(apps []string, catBits int, metric string, level float64, maxRelCI float64) [][]SlowdownCellT {
+	ret := make([][]SlowdownCellT, len(apps))
+
+	for i, app := range apps {
+		ret[i] = make([]SlowdownCellT, len(apps))
+		ref := s.ReferenceRuntime(app)
+
+		for j, cosched := range apps {
+			co := s.Runtime(ConfigT{App: app, CoSched: cosched, CATBits: catBits})
+			if co == nil || ref == nil {
+				continue
+			}
+
+			cell := &ret[i][j]
+			cell.Slowdown = s.Slowdown(co, ref, metric)
+			if math.IsNaN(cell.Slowdown) || math.IsInf(cell.Slowdown, 0) {
+				continue
+			}
+			cell.Valid = true
+
+			ci, ok := s.BootstrapSlowdownCI(co, ref, metric, level)
+			cell.CI = ci
+			cell.LowConfidence = !ok || (ci[1]-ci[0])/2 > maxRelCI*cell.Slowdown
+		}
+	}
+
+	return ret
+}
+
+// GetCoSchedCATBits returns every number of CAT bits any of the applications was co-scheduled with
+func GetCoSchedCATBits(apps []string) []int {
+	return runtimeStats.CoSchedCATBits(apps)
+}
+
+// CoSchedCATBits returns every number of CAT bits any of the applications was co-scheduled with
+func (s *StatsT) CoSchedCATBits(apps []string) []int {
+	seen := make(map[int]bool)
+	for _, app := range apps {
+		for _, cosched := range apps {
+			rs := s.CoSchedCATRuntimes(app, cosched)
+			if rs == nil {
+				continue
+			}
+			for k := range *rs {
+				seen[k] = true
+			}
+		}
+	}
+
+	var ret []int
+	for k := range seen {
+		ret = append(ret, k)
+	}
+	sort.Ints(ret)
+	return ret
+}
@@ -0,0 +1,53 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGetSlowdownMatrix(t *testing.T) {
+	saved := runtimeStats
+	defer func() { runtimeStats = saved }()
+
+	aCoSched := map[string]RuntimeT{"b": newRuntimeT(NoCATMask, secondsToRuns(3, 3, 3))}
+	aCAT := map[string]map[int]RuntimeT{"b": {2: newRuntimeT(0x3, secondsToRuns(4, 4))}}
+	bCoSched := map[string]RuntimeT{"a": newRuntimeT(NoCATMask, secondsToRuns(1, 3))}
+	runtimeStats = StatsT{
+		Runtimes: map[string]*RuntimePerAppT{
+			"a": {ReferenceRuntimes: newRuntimeT(NoCATMask, secondsToRuns(2, 2, 2, 2)), CoSchedRuntimes: &aCoSched, CoSchedCATRuntimes: &aCAT},
+			"b": {ReferenceRuntimes: newRuntimeT(NoCATMask, secondsToRuns(1, 1)), CoSchedRuntimes: &bCoSched},
+		},
+	}
+	apps := []string{"a", "b"}
+
+	m := GetSlowdownMatrix(apps, NoCATMask, RuntimeMetric, DefaultCILevel, 0.05)
+	if len(m) != 2 || len(m[0]) != 2 || len(m[1]) != 2 {
+		t.Fatalf("Unexpected matrix size %v", m)
+	}
+	// not co-scheduled with themselves
+	if m[0][0].Valid || m[1][1].Valid {
+		t.Errorf("Unmeasured pairs are valid: %+v, %+v", m[0][0], m[1][1])
+	}
+	if c := m[0][1]; !c.Valid || math.Abs(c.Slowdown-1.5) > 1e-9 || c.LowConfidence || c.CI != [2]float64{1.5, 1.5} {
+		t.Errorf("Unexpected cell a/b %+v", c)
+	}
+	// the runtimes of b vary between 1 and 3 seconds
+	if c := m[1][0]; !c.Valid || math.Abs(c.Slowdown-2) > 1e-9 || !c.LowConfidence {
+		t.Errorf("Unexpected cell b/a %+v", c)
+	}
+	// a wide interval is accepted with a larger maxRelCI
+	if c := GetSlowdownMatrix(apps, NoCATMask, RuntimeMetric, DefaultCILevel, 10)[1][0]; c.LowConfidence {
+		t.Errorf("Cell b/a has low confidence with maxRelCI 10: %+v", c)
+	}
+
+	m = GetSlowdownMatrix(apps, 2, RuntimeMetric, DefaultCILevel, 0.05)
+	if c := m[0][1]; !c.Valid || math.Abs(c.Slowdown-2) > 1e-9 || c.LowConfidence {
+		t.Errorf("Unexpected CAT cell a/b %+v", c)
+	}
+	if m[1][0].Valid || m[0][0].Valid {
+		t.Errorf("Pairs not measured with 2 CAT bits are valid: %+v", m)
+	}
+	if m = GetSlowdownMatrix(apps, 4, RuntimeMetric, DefaultCILevel, 0.05); m[0][1].Valid {
+		t.Errorf("Pair not measured with 4 CAT bits is valid: %+v", m[0][1])
+	}
+}
@@ -0,0 +1,118 @@
+package stats
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// command line options that change the meaning of the measurements. Datasets differing in them are not merged.
+var semanticOptions = map[string]bool{
+	"CPUs":         true,
+	"Threads":      true,
+	"HermitCore":   true,
+	"Launcher":     true,
+	"Launchers":    true,
+	"Cgroups":      true,
+	"PerfStat":     true,
+	"PerfInterval": true,
+}
+
+// Merge combines the raw runs of all datasets per configuration into a new dataset and recomputes all statistics. The
+// command line of the first dataset is used, differences are returned as warnings. Datasets with a different hardware
+// or run semantics are refused unless force is set.
+func Merge(datasets []*StatsT, force bool) (merged *StatsT, warnings []string, err error) {
+	if len(datasets) == 0 {
+		return nil, nil, fmt.Errorf("No datasets to merge")
+	}
+
+	first := datasets[0]
+	merged = &StatsT{Commandline: first.Commandline, Hardware: first.Hardware, MetricRules: first.MetricRules}
+
+	refuse := func(format string, a ...interface{}) {
+		msg := fmt.Sprintf(format, a...)
+		if force {
+			warnings = append(warnings, msg)
+		} else if err == nil {
+			err = fmt.Errorf("%v", msg)
+		}
+	}
+
+	for i, d := range datasets[1:] {
+		i++
+
+		if d.Hardware != first.Hardware {
+			refuse("Dataset %v was measured on different hardware: %+v instead of %+v", i, d.Hardware, first.Hardware)
+		}
+
+		a := reflect.ValueOf(first.Commandline)
+		b := reflect.ValueOf(d.Commandline)
+		for f := 0; f < a.NumField(); f++ {
+			name := a.Type().Field(f).Name
+			if reflect.DeepEqual(a.Field(f).Interface(), b.Field(f).Interface()) {
+				continue
+			}
+			switch {
+			case semanticOptions[name]:
+				refuse("Dataset %v uses %v = %v instead of %v", i, name, b.Field(f).Interface(), a.Field(f).Interface())
+			case name == "Commands":
+				merged.Commandline.Commands = mergeCommands(merged.Commandline.Commands, d.Commandline.Commands)
+			case name == "Runs":
+				if d.Commandline.Runs > merged.Commandline.Runs {
+					merged.Commandline.Runs = d.Commandline.Runs
+				}
+			case name == "CAT":
+				merged.Commandline.CAT = true
+			default:
+				warnings = append(warnings, fmt.Sprintf("Dataset %v uses %v = %v, using %v of dataset 0", i, name, b.Field(f).Interface(), a.Field(f).Interface()))
+			}
+		}
+
+		if !reflect.DeepEqual(d.MetricRules, first.MetricRules) {
+			warnings = append(warnings, fmt.Sprintf("Dataset %v uses different metric rules, using the rules of dataset 0", i))
+		}
+	}
+
+	if err != nil {
+		return nil, warnings, err
+	}
+
+	// statistics depend on the command line of the merged dataset, e.g. the outlier detection
+	for _, d := range datasets {
+		for _, c := range d.Configs() {
+			for mask, data := range *d.Runtime(c).RawRuntimesByMask {
+				merged.addRuns(c, mask, append([]DataPerRun{}, data...))
+			}
+		}
+	}
+	merged.RecomputeMetrics()
+
+	return merged, warnings, nil
+}
+
+// union of both command lists keeping the order
+func mergeCommands(a []string, b []string) []string {
+	ret := append([]string{}, a...)
+	for _, c := range b {
+		found := false
+		for _, o := range ret {
+			if o == c {
+				found = true
+				break
+			}
+		}
+		if !found {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+// adds data measured with mask to the configuration and updates its statistics
+func (s *StatsT) addRuns(c ConfigT, mask uint64, data []DataPerRun) {
+	var r RuntimeT
+	if old := s.Runtime(c); old != nil {
+		r = *old
+	}
+	r.update(mask, data, &s.Commandline)
+	s.SetRuntime(c, r)
+}
@@ -0,0 +1,47 @@
+package stats
+
+import (
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	newDataset := func(apps []string, runs int, seconds ...float64) *StatsT {
+		catRuntimes := map[int]RuntimeT{2: newRuntimeT(0x3, secondsToRuns(seconds...))}
+		return &StatsT{
+			Commandline: CommandlineT{Commands: apps, Runs: runs, Threads: "4"},
+			Hardware:    HardwareT{L3Size: 1024, L3Ways: 4, L3Caches: 1, CATBits: 4},
+			Runtimes: map[string]*RuntimePerAppT{
+				"app": {ReferenceRuntimes: newRuntimeT(NoCATMask, secondsToRuns(seconds...)), CATRuntimes: &catRuntimes},
+			},
+		}
+	}
+
+	d0 := newDataset([]string{"app"}, 3, 1, 1, 1)
+	d1 := newDataset([]string{"app", "other"}, 5, 3, 3, 3)
+
+	merged, warnings, err := Merge([]*StatsT{d0, d1}, false)
+	if err != nil || len(warnings) != 0 {
+		t.Fatalf("Merge failed: %v %v", warnings, err)
+	}
+	for _, r := range []*RuntimeT{merged.Runtime(ConfigT{App: "app"}), merged.Runtime(ConfigT{App: "app", CATBits: 2})} {
+		if r == nil || r.Runs != 6 || r.Mean != 2 {
+			t.Errorf("Unexpected merged runtime %+v", r)
+		}
+	}
+	if c := merged.Commandline; len(c.Commands) != 2 || c.Runs != 5 {
+		t.Errorf("Unexpected merged command line %+v", c)
+	}
+	// the datasets must not be changed
+	if d0.Runtime(ConfigT{App: "app"}).Runs != 3 {
+		t.Errorf("Merge changed the input")
+	}
+
+	d1.Hardware.L3Size = 2048
+	d1.Commandline.Threads = "8"
+	if _, _, err = Merge([]*StatsT{d0, d1}, false); err == nil {
+		t.Errorf("Merging different hardware must fail")
+	}
+	if _, warnings, err = Merge([]*StatsT{d0, d1}, true); err != nil || len(warnings) != 2 {
+		t.Errorf("Forced merge must only warn: %v %v", warnings, err)
+	}
+}
@@ -0,0 +1,251 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/montanaflynn/stats"
+)
+
+// RuntimeMetric is the name of the metric referring to the runtime of a run
+const RuntimeMetric = "runtime"
+
+// MetricRuleT describes how a metric reported by an application is extracted from its output
+type MetricRuleT struct {
+	// regular expression matched against the command, empty matches every command
+	Command string
+	Name    string
+	Unit    string
+	// regular expression matched against the output, the first capture group is the value
+	Regex          string
+	HigherIsBetter bool
+}
+
+// ReadMetricRules reads a json file containing a list of metric rules
+func ReadMetricRules(filename string) ([]MetricRuleT, error) {
+	raw, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []MetricRuleT
+	if err = json.Unmarshal(raw, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// SetMetricRules validates and stores the metric rules used for all runtimes added afterwards to the stats of the
+// package
+func SetMetricRules(rules []MetricRuleT) error {
+	return runtimeStats.SetMetricRules(rules)
+}
+
+// SetMetricRules validates and stores the metric rules used for all runtimes added afterwards
+func (s *StatsT) SetMetricRules(rules []MetricRuleT) error {
+	for _, rule := range rules {
+		if rule.Name == "" || rule.Name == RuntimeMetric {
+			return fmt.Errorf("Invalid metric name '%v'", rule.Name)
+		}
+		if _, err := regexp.Compile(rule.Command); err != nil {
+			return fmt.Errorf("Invalid command regex of metric %v: %v", rule.Name, err)
+		}
+		r, err := regexp.Compile(rule.Regex)
+		if err != nil {
+			return fmt.Errorf("Invalid regex of metric %v: %v", rule.Name, err)
+		}
+		if r.NumSubexp() < 1 {
+			return fmt.Errorf("Regex of metric %v has no capture group", rule.Name)
+		}
+	}
+
+	s.MetricRules = rules
+	return nil
+}
+
+// GetMetricRule returns the rule of a metric of the stats of the package or nil if the metric is unknown
+func GetMetricRule(metric string) *MetricRuleT {
+	return runtimeStats.MetricRule(metric)
+}
+
+// MetricRule returns the rule of a metric or nil if the metric is unknown
+func (s *StatsT) MetricRule(metric string) *MetricRuleT {
+	for i := range s.MetricRules {
+		if s.MetricRules[i].Name == metric {
+			return &s.MetricRules[i]
+		}
+	}
+	return nil
+}
+
+// MetricUnit returns the unit of a metric of the stats of the package
+func MetricUnit(metric string) string {
+	return runtimeStats.MetricUnit(metric)
+}
+
+// MetricUnit returns the unit of a metric
+func (s *StatsT) MetricUnit(metric string) string {
+	if metric == RuntimeMetric || metric == "" {
+		return "s"
+	}
+	if rule := s.MetricRule(metric); rule != nil {
+		return rule.Unit
+	}
+	return ""
+}
+
+// ExtractMetrics evaluates all rules of the stats of the package matching the command on the output of a run
+func ExtractMetrics(command string, output string) map[string]float64 {
+	return runtimeStats.ExtractMetrics(command, output)
+}
+
+// ExtractMetrics evaluates all rules matching the command on the output of a run
+func (s *StatsT) ExtractMetrics(command string, output string) map[string]float64 {
+	ret := make(map[string]float64)
+
+	for _, rule := range s.MetricRules {
+		if matched, _ := regexp.MatchString(rule.Command, command); !matched {
+			continue
+		}
+		match := regexp.MustCompile(rule.Regex).FindStringSubmatch(output)
+		if match == nil {
+			continue
+		}
+		value, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(match[1]), ",", "", -1), 64)
+		if err != nil {
+			continue
+		}
+		ret[rule.Name] = value
+	}
+
+	if len(ret) == 0 {
+		return nil
+	}
+	return ret
+}
+
+func (s *StatsT) extractMetrics(application string, data []DataPerRun) {
+	if len(s.MetricRules) == 0 {
+		return
+	}
+	for i := range data {
+		data[i].Metrics = s.ExtractMetrics(application, data[i].Output)
+	}
+}
+
+// RecomputeMetrics re-evaluates the current metric rules on the stored output of every run of the stats of the package
+func RecomputeMetrics() {
+	runtimeStats.RecomputeMetrics()
+}
+
+// RecomputeMetrics re-evaluates the metric rules on the stored output of every run
+func (s *StatsT) RecomputeMetrics() {
+	for app, rt := range s.Runtimes {
+		for _, r := range rt.allRuntimes() {
+			for _, data := range *r.RawRuntimesByMask {
+				s.extractMetrics(app, data)
+			}
+		}
+	}
+}
+
+// returns every runtime of an application; the raw data is shared with the stored runtimes
+func (rt *RuntimePerAppT) allRuntimes() []RuntimeT {
+	var ret []RuntimeT
+	if rt.ReferenceRuntimes.RawRuntimesByMask != nil {
+		ret = append(ret, rt.ReferenceRuntimes)
+	}
+	if rt.CATRuntimes != nil {
+		for _, r := range *rt.CATRuntimes {
+			ret = append(ret, r)
+		}
+	}
+	if rt.CoSchedRuntimes != nil {
+		for _, r := range *rt.CoSchedRuntimes {
+			ret = append(ret, r)
+		}
+	}
+	if rt.CoSchedCATRuntimes != nil {
+		for _, m := range *rt.CoSchedCATRuntimes {
+			for _, r := range m {
+				ret = append(ret, r)
+			}
+		}
+	}
+	if rt.CgroupRuntimes != nil {
+		for _, r := range *rt.CgroupRuntimes {
+			ret = append(ret, r)
+		}
+	}
+	if rt.CoSchedCgroupRuntimes != nil {
+		for _, m := range *rt.CoSchedCgroupRuntimes {
+			for _, r := range m {
+				ret = append(ret, r)
+			}
+		}
+	}
+	return ret
+}
+
+// MetricValues returns the value of metric for every run that is not excluded
+func (run *RuntimeT) MetricValues(metric string) []float64 {
+	var ret []float64
+	if run.RawRuntimesByMask == nil {
+		return ret
+	}
+	for _, v := range *run.RawRuntimesByMask {
+		for _, r := range v {
+			if r.Excluded() {
+				continue
+			}
+			if metric == RuntimeMetric || metric == "" {
+				ret = append(ret, r.Runtime.Seconds())
+				continue
+			}
+			if value, exists := r.Metrics[metric]; exists {
+				ret = append(ret, value)
+			}
+		}
+	}
+	return ret
+}
+
+// MetricStats returns mean and standard deviation of metric
+func (run *RuntimeT) MetricStats(metric string) (mean float64, stddev float64) {
+	if metric == RuntimeMetric || metric == "" {
+		return run.Mean, run.Stddev
+	}
+
+	values := run.MetricValues(metric)
+	if len(values) == 0 {
+		return math.NaN(), math.NaN()
+	}
+	mean, _ = stats.Mean(values)
+	stddev, _ = stats.StandardDeviation(values)
+	return
+}
+
+// Slowdown returns the slowdown of run compared to ref based on metric. Values > 1 are always worse than ref.
+func Slowdown(run *RuntimeT, ref *RuntimeT, metric string) float64 {
+	return runtimeStats.Slowdown(run, ref, metric)
+}
+
+// Slowdown returns the slowdown of run compared to ref with the metric rules of the stats
+func (s *StatsT) Slowdown(run *RuntimeT, ref *RuntimeT, metric string) float64 {
+	if run == nil || ref == nil {
+		return math.NaN()
+	}
+
+	mean, _ := run.MetricStats(metric)
+	refMean, _ := ref.MetricStats(metric)
+
+	if rule := s.MetricRule(metric); rule != nil && rule.HigherIsBetter {
+		return refMean / mean
+	}
+	return mean / refMean
+}
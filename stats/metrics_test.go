@@ -0,0 +1,61 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMetricExtraction(t *testing.T) {
+	defer SetMetricRules(nil)
+
+	rules := []MetricRuleT{
+		{Command: "bt", Name: "mops", Unit: "Mop/s", Regex: `Mop/s total\s*=\s*([0-9.]+)`, HigherIsBetter: true},
+		{Command: "stream", Name: "triad", Unit: "MB/s", Regex: `Triad:\s*([0-9.]+)`, HigherIsBetter: true},
+	}
+	if err := SetMetricRules(rules); err != nil {
+		t.Fatalf("SetMetricRules failed: %v", err)
+	}
+
+	if err := SetMetricRules([]MetricRuleT{{Name: "foo", Regex: "no capture group"}}); err == nil {
+		t.Errorf("SetMetricRules should reject a regex without capture group")
+	}
+
+	ref := []DataPerRun{{Runtime: time.Second, Output: " Mop/s total     =   200.0\n"}}
+	AddReferenceRuntime("bt.C.x", ref)
+	co := []DataPerRun{{Runtime: 2 * time.Second, Output: " Mop/s total     =   100.0\n Triad: 5.0\n"}}
+	stat := AddCoSchedRuntime("bt.C.x", "stream", co)
+
+	if v := (*stat.RawRuntimesByMask)[NoCATMask][0].Metrics; len(v) != 1 || v["mops"] != 100 {
+		t.Errorf("Unexpected metrics %v", v)
+	}
+
+	if s := Slowdown(&stat, GetReferenceRuntime("bt.C.x"), "mops"); math.Abs(s-2) > 1e-9 {
+		t.Errorf("Slowdown based on mops is %v, expected 2", s)
+	}
+	if s := Slowdown(&stat, GetReferenceRuntime("bt.C.x"), RuntimeMetric); math.Abs(s-2) > 1e-9 {
+		t.Errorf("Slowdown based on runtime is %v, expected 2", s)
+	}
+}
+
+func TestRecomputeMetrics(t *testing.T) {
+	var s StatsT
+	output := " Mop/s total     =   200.0\n"
+	s.AddReferenceRuntime("bt.C.x", []DataPerRun{{Runtime: time.Second, Output: output}})
+	s.AddCgroupRuntime("bt.C.x", "mem", []DataPerRun{{Runtime: time.Second, Output: output}})
+	s.AddCoSchedCgroupRuntime("bt.C.x", "stream", "mem", []DataPerRun{{Runtime: time.Second, Output: output}})
+
+	if err := s.SetMetricRules([]MetricRuleT{{Command: "bt", Name: "mops", Regex: `Mop/s total\s*=\s*([0-9.]+)`}}); err != nil {
+		t.Fatalf("SetMetricRules failed: %v", err)
+	}
+	s.RecomputeMetrics()
+
+	for _, c := range s.Configs() {
+		if v := (*s.Runtime(c).RawRuntimesByMask)[NoCATMask][0].Metrics; v["mops"] != 200 {
+			t.Errorf("%+v has the metrics %v after recomputing", c, v)
+		}
+	}
+	if len(s.Configs()) != 3 {
+		t.Errorf("Unexpected configurations %v", s.Configs())
+	}
+}
@@ -0,0 +1,167 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/montanaflynn/stats"
+)
+
+// BaselineKindT selects how the baseline of a normalization is chosen
+type BaselineKindT int
+
+// Supported baselines
+const (
+	// individual run without CAT
+	ReferenceBaseline BaselineKindT = iota
+	// individual run with the CAT configuration resulting in the best metric
+	BestCATBaseline
+	// arbitrary configuration of the application
+	ConfigBaseline
+)
+
+// BaselineT describes the runtime used to normalize the runtimes of an application
+type BaselineT struct {
+	Kind BaselineKindT
+	// only used by ConfigBaseline: co-scheduled application ("" for individual runs) and number of CAT bits (0 without CAT)
+	CoSched string
+	CATBits int
+}
+
+// NormalizedT contains the slowdowns of a set of runs compared to a baseline. Values > 1 are always worse than the baseline.
+type NormalizedT struct {
+	// slowdown of every run that is not excluded by CAT mask
+	SlowdownsByMask map[uint64][]float64
+	// slowdown of the mean compared to the mean of the baseline
+	Mean float64
+	// standard error of Mean propagated from the standard errors of both means
+	Error float64
+	// statistics of the distribution of the slowdowns of all runs
+	Stddev float64
+	Median float64
+	P25    float64
+	P75    float64
+	Runs   int
+}
+
+// ParseBaseline parses reference, best-cat or cat:<bits>
+func ParseBaseline(s string) (BaselineT, error) {
+	switch {
+	case s == "reference":
+		return BaselineT{Kind: ReferenceBaseline}, nil
+	case s == "best-cat":
+		return BaselineT{Kind: BestCATBaseline}, nil
+	case strings.HasPrefix(s, "cat:"):
+		bits, err := strconv.Atoi(strings.TrimPrefix(s, "cat:"))
+		if err != nil || bits < 1 {
+			return BaselineT{}, fmt.Errorf("Invalid number of CAT bits in baseline %v", s)
+		}
+		return BaselineT{Kind: ConfigBaseline, CATBits: bits}, nil
+	}
+	return BaselineT{}, fmt.Errorf("Unknown baseline %v", s)
+}
+
+// GetRuntime returns the runtime of application co-scheduled with cosched ("" for individual runs) and catBits bits set in the CAT mask (0 without CAT)
+func GetRuntime(application string, cosched string, catBits int) *RuntimeT {
+	return runtimeStats.Runtime(ConfigT{App: application, CoSched: cosched, CATBits: catBits})
+}
+
+// Baseline returns the runtime selected by baseline for application
+func (s *StatsT) Baseline(application string, baseline BaselineT, metric string) *RuntimeT {
+	switch baseline.Kind {
+	case ReferenceBaseline:
+		return s.ReferenceRuntime(application)
+	case ConfigBaseline:
+		return s.Runtime(ConfigT{App: application, CoSched: baseline.CoSched, CATBits: baseline.CATBits})
+	case BestCATBaseline:
+		cat := s.CATRuntimes(application)
+		if cat == nil {
+			return nil
+		}
+		var best *RuntimeT
+		for k := range *cat {
+			r := (*cat)[k]
+			if best == nil || s.Slowdown(&r, best, metric) < 1 {
+				best = &r
+			}
+		}
+		return best
+	}
+	return nil
+}
+
+// GetBaseline returns the runtime selected by baseline for application
+func GetBaseline(application string, baseline BaselineT, metric string) *RuntimeT {
+	return runtimeStats.Baseline(application, baseline, metric)
+}
+
+// Normalize computes the slowdown of every run of run compared to the mean of baseline
+func Normalize(run *RuntimeT, baseline *RuntimeT, metric string) *NormalizedT {
+	return runtimeStats.Normalize(run, baseline, metric)
+}
+
+// Normalize computes the slowdown of every run of run compared to the mean of baseline with the metric rules of the stats
+func (s *StatsT) Normalize(run *RuntimeT, baseline *RuntimeT, metric string) *NormalizedT {
+	if run == nil || baseline == nil || run.RawRuntimesByMask == nil {
+		return nil
+	}
+
+	higherIsBetter := false
+	if rule := s.MetricRule(metric); rule != nil {
+		higherIsBetter = rule.HigherIsBetter
+	}
+	baseMean, baseStddev := baseline.MetricStats(metric)
+	baseRuns := len(baseline.MetricValues(metric))
+
+	var ret NormalizedT
+	ret.SlowdownsByMask = make(map[uint64][]float64)
+	var all []float64
+	for mask, runs := range *run.RawRuntimesByMask {
+		single := RuntimeT{RawRuntimesByMask: &map[uint64][]DataPerRun{mask: runs}}
+		for _, v := range single.MetricValues(metric) {
+			slowdown := v / baseMean
+			if higherIsBetter {
+				slowdown = baseMean / v
+			}
+			ret.SlowdownsByMask[mask] = append(ret.SlowdownsByMask[mask], slowdown)
+			all = append(all, slowdown)
+		}
+	}
+
+	ret.Runs = len(all)
+	if ret.Runs == 0 || baseRuns == 0 {
+		return nil
+	}
+
+	mean, stddev := run.MetricStats(metric)
+	ret.Mean = s.Slowdown(run, baseline, metric)
+	// R = A/B: σR = R * sqrt((σA/A)² + (σB/B)²) with the standard errors of the means
+	relA := stddev / math.Sqrt(float64(ret.Runs)) / mean
+	relB := baseStddev / math.Sqrt(float64(baseRuns)) / baseMean
+	ret.Error = math.Abs(ret.Mean) * math.Sqrt(relA*relA+relB*relB)
+
+	ret.Stddev, _ = stats.StandardDeviation(all)
+	ret.Median, _ = stats.Median(all)
+	ret.P25, _ = stats.Percentile(all, 25)
+	ret.P75, _ = stats.Percentile(all, 75)
+
+	return &ret
+}
+
+func (s *StatsT) normalizeMap(application string, rs *map[int]RuntimeT, baseline BaselineT, metric string) *map[int]NormalizedT {
+	base := s.Baseline(application, baseline, metric)
+	if base == nil || rs == nil {
+		return nil
+	}
+
+	ret := make(map[int]NormalizedT)
+	for k := range *rs {
+		r := (*rs)[k]
+		if n := s.Normalize(&r, base, metric); n != nil {
+			ret[k] = *n
+		}
+	}
+	return &ret
+}
@@ -0,0 +1,54 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func secondsToRuns(seconds ...float64) []DataPerRun {
+	ret := make([]DataPerRun, len(seconds))
+	for i, s := range seconds {
+		ret[i].Runtime = time.Duration(s * float64(time.Second))
+	}
+	return ret
+}
+
+func TestNormalize(t *testing.T) {
+	AddReferenceRuntime("norm0", secondsToRuns(2, 2, 2, 2))
+	AddCATRuntime("norm0", 0x3, secondsToRuns(3, 3))
+	AddCATRuntime("norm0", 0xf, secondsToRuns(1, 1))
+	AddCoSchedCATRuntime("norm0", "norm1", 0x3, secondsToRuns(3, 5))
+	AddCoSchedCATRuntime("norm0", "norm1", 0x30, secondsToRuns(4))
+
+	ref := BaselineT{Kind: ReferenceBaseline}
+	co := GetCoSchedCATRuntimesNormalized("norm0", "norm1", ref, RuntimeMetric)
+	if co == nil {
+		t.Fatalf("GetCoSchedCATRuntimesNormalized returned nil")
+	}
+	n := (*co)[2]
+	if math.Abs(n.Mean-2) > 1e-9 || n.Runs != 3 {
+		t.Errorf("Expected slowdown 2 of 3 runs, got %v of %v runs", n.Mean, n.Runs)
+	}
+	if len(n.SlowdownsByMask) != 2 || len(n.SlowdownsByMask[0x3]) != 2 || math.Abs(n.SlowdownsByMask[0x3][0]-1.5) > 1e-9 {
+		t.Errorf("Per mask breakdown lost: %v", n.SlowdownsByMask)
+	}
+	if n.Error <= 0 {
+		t.Errorf("Expected a propagated error > 0, got %v", n.Error)
+	}
+
+	best, err := ParseBaseline("best-cat")
+	if err != nil {
+		t.Fatalf("ParseBaseline failed: %v", err)
+	}
+	r := GetReferenceRuntimeNormalized("norm0", best, RuntimeMetric)
+	if r == nil || math.Abs(r.Mean-2) > 1e-9 {
+		t.Errorf("Expected slowdown 2 of the reference compared to the best CAT configuration, got %v", r)
+	}
+
+	cat, _ := ParseBaseline("cat:2")
+	c := GetCATRuntimesNormalized("norm0", cat, RuntimeMetric)
+	if c == nil || math.Abs((*c)[4].Mean-1.0/3) > 1e-9 || (*c)[2].Error != 0 {
+		t.Errorf("Unexpected normalization to cat:2: %v", c)
+	}
+}
@@ -0,0 +1,88 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/montanaflynn/stats"
+)
+
+// Supported outlier detection methods
+const (
+	NoOutlierDetection  = ""
+	IQROutlierDetection = "iqr"
+	MADOutlierDetection = "mad"
+)
+
+// minimum number of runs required before outliers are detected
+const minRunsOutlierDetection = 4
+
+// SetOutlierDetection stores the outlier detection used for all runtimes added afterwards to the stats of the package
+func SetOutlierDetection(method string, threshold float64) error {
+	return runtimeStats.SetOutlierDetection(method, threshold)
+}
+
+// SetOutlierDetection stores the outlier detection used for all runtimes added afterwards.
+// A NaN threshold selects the default of the method (1.5 for iqr, 3.5 for mad).
+func (s *StatsT) SetOutlierDetection(method string, threshold float64) error {
+	switch method {
+	case NoOutlierDetection:
+	case IQROutlierDetection:
+		if math.IsNaN(threshold) {
+			threshold = 1.5
+		}
+	case MADOutlierDetection:
+		if math.IsNaN(threshold) {
+			threshold = 3.5
+		}
+	default:
+		return fmt.Errorf("Unknown outlier detection method %v", method)
+	}
+	if threshold <= 0 {
+		return fmt.Errorf("Outlier threshold must be > 0")
+	}
+
+	s.Commandline.OutlierMethod = method
+	s.Commandline.OutlierThreshold = threshold
+	if method == NoOutlierDetection {
+		s.Commandline.OutlierThreshold = 0
+	}
+	return nil
+}
+
+// Excluded returns true if the run is not used to compute statistics
+func (data *DataPerRun) Excluded() bool {
+	return data.Warmup || data.Outlier
+}
+
+// returns true for every value that is an outlier
+func detectOutliers(values []float64, method string, threshold float64) []bool {
+	ret := make([]bool, len(values))
+	if method == NoOutlierDetection || len(values) < minRunsOutlierDetection {
+		return ret
+	}
+
+	switch method {
+	case IQROutlierDetection:
+		q, err := stats.Quartile(values)
+		if err != nil {
+			return ret
+		}
+		iqr := q.Q3 - q.Q1
+		for i, v := range values {
+			ret[i] = v < q.Q1-threshold*iqr || v > q.Q3+threshold*iqr
+		}
+	case MADOutlierDetection:
+		median, _ := stats.Median(values)
+		mad, _ := stats.MedianAbsoluteDeviationPopulation(values)
+		if mad == 0 {
+			return ret
+		}
+		for i, v := range values {
+			// modified z-score by Iglewicz and Hoaglin
+			ret[i] = math.Abs(0.6745*(v-median)/mad) > threshold
+		}
+	}
+
+	return ret
+}
@@ -0,0 +1,41 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestOutlierDetection(t *testing.T) {
+	defer SetOutlierDetection(NoOutlierDetection, math.NaN())
+
+	if err := SetOutlierDetection("foo", math.NaN()); err == nil {
+		t.Errorf("SetOutlierDetection should reject unknown methods")
+	}
+
+	for _, method := range []string{IQROutlierDetection, MADOutlierDetection} {
+		if err := SetOutlierDetection(method, math.NaN()); err != nil {
+			t.Fatalf("SetOutlierDetection(%v) failed: %v", method, err)
+		}
+
+		seconds := []float64{100, 10, 10.1, 9.9, 10, 10.2, 50}
+		data := make([]DataPerRun, len(seconds))
+		for i, s := range seconds {
+			data[i].Runtime = time.Duration(s * float64(time.Second))
+		}
+		data[0].Warmup = true
+
+		r := newRuntimeT(NoCATMask, data)
+		raw := (*r.RawRuntimesByMask)[NoCATMask]
+
+		if r.Runs != 5 || r.Outliers != 1 || !raw[6].Outlier {
+			t.Errorf("%v: expected 5 runs and the last run as outlier, got %v runs and %v outliers", method, r.Runs, r.Outliers)
+		}
+		if raw[0].Outlier || !raw[0].Excluded() {
+			t.Errorf("%v: warm-up run must be excluded, but not flagged as outlier", method)
+		}
+		if math.Abs(r.Mean-10.04) > 1e-6 {
+			t.Errorf("%v: mean %v includes excluded runs", method, r.Mean)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// PairMetricsT contains system level metrics of two co-scheduled applications.
+// The normalized progress NP of an application is 1/slowdown.
+type PairMetricsT struct {
+	// slowdown of both applications compared to their reference runtime
+	Slowdowns [2]float64
+	// system throughput: Σ NP
+	STP float64
+	// weighted speedup: Σ NP / 2, 1 means both applications progress as fast as running alone
+	WeightedSpeedup float64
+	// average normalized turnaround time: Σ 1/NP / 2
+	ANTT float64
+	// Jain's fairness index of the normalized progress, 1 is perfectly fair
+	Fairness float64
+	// runtime of one execution of both applications co-scheduled and one after another (in seconds)
+	Makespan   float64
+	Sequential float64
+	// Makespan / Sequential, < 1 means co-scheduling is faster than sequential execution
+	MakespanRatio float64
+}
+
+// ComputePairMetrics computes the pair metrics of two co-scheduled runtimes co based on the reference runtimes ref
+func ComputePairMetrics(co [2]*RuntimeT, ref [2]*RuntimeT, metric string) *PairMetricsT {
+	return runtimeStats.ComputePairMetrics(co, ref, metric)
+}
+
+// ComputePairMetrics computes the pair metrics of two co-scheduled runtimes co based on the reference runtimes ref with the
+// metric rules of the stats
+func (s *StatsT) ComputePairMetrics(co [2]*RuntimeT, ref [2]*RuntimeT, metric string) *PairMetricsT {
+	for i := range co {
+		if co[i] == nil || ref[i] == nil {
+			return nil
+		}
+	}
+
+	var ret PairMetricsT
+	var np [2]float64
+	sumNP, sumNP2 := 0.0, 0.0
+	for i := range co {
+		ret.Slowdowns[i] = s.Slowdown(co[i], ref[i], metric)
+		np[i] = 1 / ret.Slowdowns[i]
+		sumNP += np[i]
+		sumNP2 += np[i] * np[i]
+		ret.ANTT += ret.Slowdowns[i] / float64(len(co))
+		ret.Sequential += ref[i].Mean
+	}
+
+	ret.STP = sumNP
+	ret.WeightedSpeedup = sumNP / float64(len(co))
+	ret.Fairness = sumNP * sumNP / (float64(len(co)) * sumNP2)
+	ret.Makespan = math.Max(co[0].Mean, co[1].Mean)
+	ret.MakespanRatio = ret.Makespan / ret.Sequential
+
+	for _, v := range []float64{ret.STP, ret.ANTT, ret.Fairness, ret.MakespanRatio} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil
+		}
+	}
+
+	return &ret
+}
+
+// CoSchedPairMetrics returns the pair metrics of app0 and app1 co-scheduled without CAT
+func (s *StatsT) CoSchedPairMetrics(app0 string, app1 string, metric string) *PairMetricsT {
+	co := [2]*RuntimeT{s.CoSchedRuntimes(app0, app1), s.CoSchedRuntimes(app1, app0)}
+	ref := [2]*RuntimeT{s.ReferenceRuntime(app0), s.ReferenceRuntime(app1)}
+	return s.ComputePairMetrics(co, ref, metric)
+}
+
+// GetCoSchedPairMetrics returns the pair metrics of app0 and app1 co-scheduled without CAT
+func GetCoSchedPairMetrics(app0 string, app1 string, metric string) *PairMetricsT {
+	return runtimeStats.CoSchedPairMetrics(app0, app1, metric)
+}
+
+// CoSchedCATPairMetrics returns the pair metrics of app0 and app1 co-scheduled with CAT.
+// The key is the number of CAT bits of app0, app1 used the remaining bits.
+func (s *StatsT) CoSchedCATPairMetrics(app0 string, app1 string, metric string) *map[int]PairMetricsT {
+	splits := s.CoSchedCATSplits(app0, app1)
+	if splits == nil {
+		return nil
+	}
+	ref := [2]*RuntimeT{s.ReferenceRuntime(app0), s.ReferenceRuntime(app1)}
+
+	ret := make(map[int]PairMetricsT)
+	for _, split := range splits {
+		co := [2]*RuntimeT{s.Runtime(ConfigT{App: app0, CoSched: app1, CATBits: split[0]}), s.Runtime(ConfigT{App: app1, CoSched: app0, CATBits: split[1]})}
+		if m := s.ComputePairMetrics(co, ref, metric); m != nil {
+			ret[split[0]] = *m
+		}
+	}
+	return &ret
+}
+
+// GetCoSchedCATPairMetrics returns the pair metrics of app0 and app1 co-scheduled with CAT.
+// The key is the number of CAT bits of app0, app1 used the remaining bits.
+func GetCoSchedCATPairMetrics(app0 string, app1 string, metric string) *map[int]PairMetricsT {
+	return runtimeStats.CoSchedCATPairMetrics(app0, app1, metric)
+}
+
+// GetCoSchedCATSplits returns the number of CAT bits of app0 and app1 for every CAT configuration they were co-scheduled with
+func GetCoSchedCATSplits(app0 string, app1 string) [][2]int {
+	return runtimeStats.CoSchedCATSplits(app0, app1)
+}
+
+// CoSchedCATSplits returns the number of CAT bits of app0 and app1 for every CAT configuration they were co-scheduled with
+func (s *StatsT) CoSchedCATSplits(app0 string, app1 string) [][2]int {
+	r0 := s.CoSchedCATRuntimes(app0, app1)
+	r1 := s.CoSchedCATRuntimes(app1, app0)
+	if r0 == nil || r1 == nil || len(*r0) != len(*r1) {
+		return nil
+	}
+
+	keys0, keys1 := SortedCATKeys(r0), SortedCATKeys(r1)
+	ret := make([][2]int, len(keys0))
+	for i, k0 := range keys0 {
+		// app0 with the fewest bits was co-scheduled with app1 with the most bits
+		ret[i] = [2]int{k0, keys1[len(keys1)-i-1]}
+	}
+	return ret
+}
+
+// SortedCATKeys returns the number of CAT bits used in r in ascending order
+func SortedCATKeys(r *map[int]RuntimeT) []int {
+	var keys []int
+	for k := range *r {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
@@ -0,0 +1,39 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestComputePairMetrics(t *testing.T) {
+	ref0, ref1 := newRuntimeT(NoCATMask, secondsToRuns(1, 1)), newRuntimeT(NoCATMask, secondsToRuns(2, 2))
+	co0, co1 := newRuntimeT(NoCATMask, secondsToRuns(2, 2)), newRuntimeT(NoCATMask, secondsToRuns(2, 2))
+
+	m := ComputePairMetrics([2]*RuntimeT{&co0, &co1}, [2]*RuntimeT{&ref0, &ref1}, RuntimeMetric)
+	if m == nil {
+		t.Fatalf("ComputePairMetrics returned nil")
+	}
+
+	// slowdowns 2 and 1, NP 0.5 and 1
+	expected := []struct {
+		name            string
+		value, expected float64
+	}{
+		{"STP", m.STP, 1.5},
+		{"WeightedSpeedup", m.WeightedSpeedup, 0.75},
+		{"ANTT", m.ANTT, 1.5},
+		{"Fairness", m.Fairness, 2.25 / 2.5},
+		{"Makespan", m.Makespan, 2},
+		{"Sequential", m.Sequential, 3},
+		{"MakespanRatio", m.MakespanRatio, 2.0 / 3},
+	}
+	for _, e := range expected {
+		if math.Abs(e.value-e.expected) > 1e-9 {
+			t.Errorf("%v is %v, expected %v", e.name, e.value, e.expected)
+		}
+	}
+
+	if ComputePairMetrics([2]*RuntimeT{&co0, nil}, [2]*RuntimeT{&ref0, &ref1}, RuntimeMetric) != nil {
+		t.Errorf("ComputePairMetrics must return nil if a runtime is missing")
+	}
+}
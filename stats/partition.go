@@ -0,0 +1,156 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+)
+
+// Objectives supported by FindBestPartition
+const (
+	// minimize the sum of the slowdowns
+	SumSlowdownObjective = "sum"
+	// minimize the maximum slowdown
+	MaxSlowdownObjective = "max"
+	// maximize the weighted speedup
+	WeightedSpeedupObjective = "ws"
+	// keep the slowdown of one application below a target and minimize the slowdown of the other one
+	QoSObjective = "qos"
+)
+
+// ObjectiveT selects what FindBestPartition optimizes
+type ObjectiveT struct {
+	Kind string
+	// only used by QoSObjective
+	QoSApp    string
+	QoSTarget float64
+}
+
+// PartitionCandidateT is one possible cache partition of two co-scheduled applications
+type PartitionCandidateT struct {
+	// CAT bits of both applications, 0 means the cache is shared without partitioning
+	CATBits   [2]int
+	Slowdowns [2]float64
+	// bootstrap confidence intervals of the slowdowns, zero if not available
+	SlowdownCIs [2][2]float64
+	Metrics     PairMetricsT
+	Objective   float64
+	// interval of the objective derived from the confidence intervals of the slowdowns
+	ObjectiveCI [2]float64
+	// false if the candidate violates the QoS target
+	Feasible bool
+}
+
+// PartitionRecommendationT is the result of FindBestPartition
+type PartitionRecommendationT struct {
+	Apps      [2]string
+	Objective ObjectiveT
+	// nil if no candidate is feasible
+	Best       *PartitionCandidateT
+	Candidates []PartitionCandidateT
+}
+
+// ParseObjective parses sum, max, ws or qos. qosApp and qosTarget are only used by qos.
+func ParseObjective(kind string, qosApp string, qosTarget float64) (ObjectiveT, error) {
+	switch kind {
+	case SumSlowdownObjective, MaxSlowdownObjective, WeightedSpeedupObjective:
+		return ObjectiveT{Kind: kind}, nil
+	case QoSObjective:
+		if qosApp == "" || qosTarget < 1 {
+			return ObjectiveT{}, fmt.Errorf("qos requires an application and a target slowdown >= 1")
+		}
+		return ObjectiveT{Kind: kind, QoSApp: qosApp, QoSTarget: qosTarget}, nil
+	}
+	return ObjectiveT{}, fmt.Errorf("Unknown objective %v", kind)
+}
+
+// returns true if a is a better value of the objective than b
+func (o ObjectiveT) better(a float64, b float64) bool {
+	if o.Kind == WeightedSpeedupObjective {
+		return a > b
+	}
+	return a < b
+}
+
+func (o ObjectiveT) evaluate(apps [2]string, c *PartitionCandidateT) {
+	ci0, ci1 := c.SlowdownCIs[0], c.SlowdownCIs[1]
+	c.Feasible = true
+
+	switch o.Kind {
+	case SumSlowdownObjective:
+		c.Objective = c.Slowdowns[0] + c.Slowdowns[1]
+		c.ObjectiveCI = [2]float64{ci0[0] + ci1[0], ci0[1] + ci1[1]}
+	case MaxSlowdownObjective:
+		c.Objective = math.Max(c.Slowdowns[0], c.Slowdowns[1])
+		c.ObjectiveCI = [2]float64{math.Max(ci0[0], ci1[0]), math.Max(ci0[1], ci1[1])}
+	case WeightedSpeedupObjective:
+		// the lowest weighted speedup results from the highest slowdowns
+		c.Objective = c.Metrics.WeightedSpeedup
+		c.ObjectiveCI = [2]float64{(1/ci0[1] + 1/ci1[1]) / 2, (1/ci0[0] + 1/ci1[0]) / 2}
+	case QoSObjective:
+		qos := 0
+		if apps[1] == o.QoSApp {
+			qos = 1
+		}
+		c.Feasible = c.Slowdowns[qos] <= o.QoSTarget
+		c.Objective = c.Slowdowns[(qos+1)%2]
+		c.ObjectiveCI = c.SlowdownCIs[(qos+1)%2]
+	}
+
+	// do not report intervals based on missing confidence intervals
+	for _, ci := range c.SlowdownCIs {
+		if ci[0] == 0 && ci[1] == 0 {
+			c.ObjectiveCI = [2]float64{}
+		}
+	}
+}
+
+// FindBestPartition evaluates sharing the cache without partitioning and every CAT configuration app0 and app1 were
+// co-scheduled with and returns the candidate optimizing objective
+func (s *StatsT) FindBestPartition(app0 string, app1 string, objective ObjectiveT, metric string, level float64) *PartitionRecommendationT {
+	if objective.Kind == QoSObjective && objective.QoSApp != app0 && objective.QoSApp != app1 {
+		return nil
+	}
+
+	apps := [2]string{app0, app1}
+	ref := [2]*RuntimeT{s.ReferenceRuntime(app0), s.ReferenceRuntime(app1)}
+
+	configs := [][2]int{{NoCATMask, NoCATMask}}
+	configs = append(configs, s.CoSchedCATSplits(app0, app1)...)
+
+	ret := PartitionRecommendationT{Apps: apps, Objective: objective}
+	for _, config := range configs {
+		co := [2]*RuntimeT{s.Runtime(ConfigT{App: app0, CoSched: app1, CATBits: config[0]}), s.Runtime(ConfigT{App: app1, CoSched: app0, CATBits: config[1]})}
+		m := s.ComputePairMetrics(co, ref, metric)
+		if m == nil {
+			continue
+		}
+
+		c := PartitionCandidateT{CATBits: config, Slowdowns: m.Slowdowns, Metrics: *m}
+		for i := range co {
+			if ci, ok := s.BootstrapSlowdownCI(co[i], ref[i], metric, level); ok {
+				c.SlowdownCIs[i] = ci
+			}
+		}
+		objective.evaluate(apps, &c)
+
+		ret.Candidates = append(ret.Candidates, c)
+	}
+
+	if len(ret.Candidates) == 0 {
+		return nil
+	}
+
+	for i := range ret.Candidates {
+		c := &ret.Candidates[i]
+		if c.Feasible && (ret.Best == nil || objective.better(c.Objective, ret.Best.Objective)) {
+			ret.Best = c
+		}
+	}
+
+	return &ret
+}
+
+// FindBestPartition returns the candidate optimizing objective of the stats of the package, see StatsT.FindBestPartition
+func FindBestPartition(app0 string, app1 string, objective ObjectiveT, metric string, level float64) *PartitionRecommendationT {
+	return runtimeStats.FindBestPartition(app0, app1, objective, metric, level)
+}
@@ -0,0 +1,43 @@
+package stats
+
+import (
+	"testing"
+)
+
+func TestFindBestPartition(t *testing.T) {
+	AddReferenceRuntime("part0", secondsToRuns(1, 1))
+	AddReferenceRuntime("part1", secondsToRuns(1, 1))
+	AddCoSchedRuntime("part0", "part1", secondsToRuns(2, 2))
+	AddCoSchedRuntime("part1", "part0", secondsToRuns(2, 2))
+	// part0 with 2 bits, part1 with 6 bits
+	AddCoSchedCATRuntime("part0", "part1", 0x03, secondsToRuns(1.8, 1.8))
+	AddCoSchedCATRuntime("part1", "part0", 0xfc, secondsToRuns(1.1, 1.1))
+	// part0 with 6 bits, part1 with 2 bits
+	AddCoSchedCATRuntime("part0", "part1", 0x3f, secondsToRuns(1.05, 1.05))
+	AddCoSchedCATRuntime("part1", "part0", 0xc0, secondsToRuns(1.5, 1.5))
+
+	expected := []struct {
+		objective ObjectiveT
+		bits      [2]int
+	}{
+		{ObjectiveT{Kind: SumSlowdownObjective}, [2]int{6, 2}},
+		{ObjectiveT{Kind: MaxSlowdownObjective}, [2]int{6, 2}},
+		{ObjectiveT{Kind: WeightedSpeedupObjective}, [2]int{6, 2}},
+		{ObjectiveT{Kind: QoSObjective, QoSApp: "part1", QoSTarget: 1.2}, [2]int{2, 6}},
+	}
+
+	for _, e := range expected {
+		rec := FindBestPartition("part0", "part1", e.objective, RuntimeMetric, DefaultCILevel)
+		if rec == nil || len(rec.Candidates) != 3 {
+			t.Fatalf("%v: expected 3 candidates, got %v", e.objective.Kind, rec)
+		}
+		if rec.Best == nil || rec.Best.CATBits != e.bits {
+			t.Errorf("%v: expected %v as best partition, got %v", e.objective.Kind, e.bits, rec.Best)
+		}
+	}
+
+	rec := FindBestPartition("part0", "part1", ObjectiveT{Kind: QoSObjective, QoSApp: "part0", QoSTarget: 1.01}, RuntimeMetric, DefaultCILevel)
+	if rec == nil || rec.Best != nil {
+		t.Errorf("No candidate should meet the QoS target")
+	}
+}
@@ -0,0 +1,249 @@
+package stats
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ParsePerfCounters extracts the counter values from the summary printed by perf stat
+func ParsePerfCounters(stdout string) map[string]float64 {
+	ret := make(map[string]float64)
+
+	/*
+		Performance counter stats for '/global/work/share/npb/bt.C.x':
+
+		  31162368.00 Bytes intel_cqm/llc_occupancy/
+		   7313911210       LLC-load-misses
+	*/
+	posHead := strings.Index(stdout, "Performance counter stats for")
+	if posHead == -1 {
+		return ret
+	}
+	posHEadLinebreak := posHead + strings.Index(stdout[posHead:], "\n")
+	if posHEadLinebreak == -1 {
+		return ret
+	}
+	lineStart := posHEadLinebreak + 2 // 2 linebreaks
+	for {
+		/* 				  31162368.00 Bytes intel_cqm/llc_occupancy/ */
+		endOfLine := strings.Index(stdout[lineStart:], "\n")
+		if endOfLine == -1 {
+			break
+		}
+
+		line := strings.TrimSpace(stdout[lineStart : lineStart+endOfLine])
+		if line == "" {
+			break
+		}
+		endNumber := strings.Index(line, " ")
+		if endNumber == -1 {
+			break
+		}
+
+		if value, err := strconv.ParseFloat(line[:endNumber], 64); err == nil {
+			beginName := strings.LastIndex(line, " ")
+			name := strings.TrimSpace(line[beginName:])
+			log.WithField("value", value).WithField("name", name).Debugln("found value")
+			ret[name] = value
+		} else {
+			log.WithError(err).WithField("line", line).Errorln("Could not extract value from perf")
+		}
+		lineStart = lineStart + endOfLine + 1
+	}
+
+	return ret
+}
+
+// perf stat -I starts every row with the time since the start in seconds with nanoseconds. The character following it
+// is the separator of -x or a space.
+var perfIntervalTime = regexp.MustCompile(`^\s*([0-9]+\.[0-9]{9})([^0-9])`)
+
+// splits the events of perf stat -e, e.g. "cycles,cpu/event=0x3c,umask=0x0/,{instructions,LLC-load-misses}". Commas in
+// the terms of raw events are kept, groups are removed.
+func perfEventNames(events string) []string {
+	var ret []string
+	var current strings.Builder
+	inTerm := false
+	for _, c := range events {
+		switch {
+		case c == '/':
+			inTerm = !inTerm
+		case c == '{' || c == '}':
+			continue
+		case c == ',' && !inTerm:
+			if current.Len() > 0 {
+				ret = append(ret, current.String())
+			}
+			current.Reset()
+			continue
+		}
+		current.WriteRune(c)
+	}
+	if current.Len() > 0 {
+		ret = append(ret, current.String())
+	}
+	return ret
+}
+
+// name of an event without its PMU and modifiers, e.g. LLC-load-misses for cpu_core/LLC-load-misses/ of hybrid CPUs or
+// LLC-load-misses:u. Raw events keep their PMU.
+func perfEventBase(name string) string {
+	if first, last := strings.Index(name, "/"), strings.LastIndex(name, "/"); first != last {
+		if term := name[first+1 : last]; !strings.Contains(term, "=") {
+			return term
+		}
+		return name[:last+1]
+	}
+	if pos := strings.Index(name, ":"); pos != -1 {
+		return name[:pos]
+	}
+	return name
+}
+
+// counts are printed with thousands separators without -x
+func parsePerfCount(s string) (float64, bool) {
+	v, err := strconv.ParseFloat(strings.Replace(s, ",", "", -1), 64)
+	return v, err == nil
+}
+
+// parses a row of perf stat -I into its time, count and event. Rows of counters perf could not read, e.g. <not counted>,
+// are not samples.
+func parsePerfIntervalRow(line string) (sample PerfSampleT, event string, ok bool) {
+	/*
+		     1.000183237              6,021      LLC-load-misses           #    0.50% of all LL-cache accesses
+		     1.000183237 S0-D0-C0           2        31162368.00 Bytes intel_cqm/llc_occupancy/
+		     1.000183237 CPU0              6,021      cpu_core/LLC-load-misses/                      (50.00%)
+		1.000183237,6021,,LLC-load-misses,1000123,100.00,,
+	*/
+	m := perfIntervalTime.FindStringSubmatchIndex(line)
+	if m == nil {
+		return
+	}
+	sample.Time, _ = strconv.ParseFloat(line[m[2]:m[3]], 64)
+
+	var fields []string
+	rest := line[m[4]:]
+	sep := line[m[4]:m[5]]
+	separated := sep != " " && sep != "\t"
+	if !separated {
+		// remove comments perf appends to some counters
+		if pos := strings.Index(rest, "#"); pos != -1 {
+			rest = rest[:pos]
+		}
+		fields = strings.Fields(rest)
+		// multiplexing ratio
+		for len(fields) > 0 && strings.HasPrefix(fields[len(fields)-1], "(") {
+			fields = fields[:len(fields)-1]
+		}
+	} else {
+		// -x: count, unit, event, run time, ratio, ...
+		fields = strings.Split(rest[len(sep):], sep)
+	}
+	if len(fields) < 2 {
+		return
+	}
+
+	// aggregation of --per-core, --per-socket or -A, the former followed by the number of CPUs
+	if _, isCount := parsePerfCount(fields[0]); !isCount && !strings.HasPrefix(fields[0], "<") {
+		fields = fields[1:]
+		if len(fields) >= 2 {
+			_, cpus := parsePerfCount(fields[0])
+			_, count := parsePerfCount(fields[1])
+			if cpus && (count || strings.HasPrefix(fields[1], "<")) {
+				fields = fields[1:]
+			}
+		}
+	}
+	if len(fields) < 2 {
+		return
+	}
+
+	if sample.Value, ok = parsePerfCount(fields[0]); !ok {
+		return
+	}
+	if separated {
+		if len(fields) < 3 {
+			return sample, "", false
+		}
+		event = fields[2]
+	} else {
+		// the unit is optional
+		event = fields[len(fields)-1]
+	}
+	ok = event != ""
+	return
+}
+
+// ParsePerfIntervals extracts the counter time series from the output of perf stat -I -e events. The application
+// writes to the same output, so only rows structured like those of perf stat -I with a counter of events are used. All
+// counters are used if events is empty. Events match independent of their PMU and modifiers, e.g. LLC-load-misses
+// matches cpu_core/LLC-load-misses/. Counts of multiple CPUs or cores at the same time are summed up.
+func ParsePerfIntervals(output string, events string) map[string][]PerfSampleT {
+	ret := make(map[string][]PerfSampleT)
+
+	names := make(map[string]bool)
+	for _, name := range perfEventNames(events) {
+		names[perfEventBase(name)] = true
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		sample, name, ok := parsePerfIntervalRow(line)
+		if !ok {
+			continue
+		}
+		if len(names) > 0 && !names[perfEventBase(name)] {
+			continue
+		}
+
+		series := ret[name]
+		if n := len(series); n > 0 && series[n-1].Time == sample.Time {
+			series[n-1].Value += sample.Value
+			continue
+		}
+		ret[name] = append(series, sample)
+	}
+
+	if len(ret) == 0 {
+		return nil
+	}
+	return ret
+}
+
+// PerfSeriesNames returns the sorted names of all perf counters with a time series in rt
+func PerfSeriesNames(rt *RuntimeT) []string {
+	if rt == nil || rt.RawRuntimesByMask == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	for _, runs := range *rt.RawRuntimesByMask {
+		for _, run := range runs {
+			for name := range run.PerfSeries {
+				seen[name] = true
+			}
+		}
+	}
+
+	var names []string
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// SetPerf stores the perf options in the config struct
+func (s *StatsT) SetPerf(perfStat string, interval int) {
+	s.Commandline.PerfStat = perfStat
+	s.Commandline.PerfInterval = interval
+}
+
+// SetPerf stores the perf options in the config struct of the stats of the package
+func SetPerf(perfStat string, interval int) {
+	runtimeStats.SetPerf(perfStat, interval)
+}
@@ -0,0 +1,102 @@
+package stats
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePerfIntervals(t *testing.T) {
+	output := `some output of the application
+#           time             counts unit events
+     1.000183237        31162368.00 Bytes intel_cqm/llc_occupancy/
+     1.000183237              6,021      LLC-load-misses
+     2.000421010        32000000.00 Bytes intel_cqm/llc_occupancy/
+     2.000421010      <not counted>      LLC-load-misses
+`
+	expected := map[string][]PerfSampleT{
+		"intel_cqm/llc_occupancy/": {{1.000183237, 31162368}, {2.000421010, 32000000}},
+		"LLC-load-misses":          {{1.000183237, 6021}},
+	}
+
+	series := ParsePerfIntervals(output, "")
+	if !reflect.DeepEqual(series, expected) {
+		t.Errorf("ParsePerfIntervals returned %v, expected %v", series, expected)
+	}
+
+	if ParsePerfIntervals("no perf output\n", "") != nil {
+		t.Errorf("ParsePerfIntervals should return nil without perf output")
+	}
+}
+
+// lines of the application looking like samples are ignored before the header and for counters not passed to perf
+func TestParsePerfIntervalsMixedOutput(t *testing.T) {
+	output := `1 2.5e-3 residual
+#           time             counts unit events
+     1.000183237              6,021      LLC-load-misses
+2 1.5e-3 residual
+     1.000183237               1200      cpu/event=0x3c,umask=0x0/
+     2.000421010               7000      LLC-load-misses
+`
+	expected := map[string][]PerfSampleT{
+		"LLC-load-misses":           {{1.000183237, 6021}, {2.000421010, 7000}},
+		"cpu/event=0x3c,umask=0x0/": {{1.000183237, 1200}},
+	}
+
+	series := ParsePerfIntervals(output, "{LLC-load-misses,cpu/event=0x3c,umask=0x0/}")
+	if !reflect.DeepEqual(series, expected) {
+		t.Errorf("ParsePerfIntervals returned %v, expected %v", series, expected)
+	}
+
+	if series := ParsePerfIntervals("1 2.5e-3 residual\n", ""); series != nil {
+		t.Errorf("ParsePerfIntervals without the header returned %v", series)
+	}
+}
+
+// rows of other output formats of perf stat -I are parsed by their fields, counts of the same time are summed up
+func TestParsePerfIntervalsFormats(t *testing.T) {
+	samples := []PerfSampleT{{1.000183237, 6021}, {2.000421010, 7000}}
+	expected := []struct {
+		name   string
+		output string
+		event  string
+	}{
+		{"other header", `#           time             counts unit events
+     1.000183237              6,021      LLC-load-misses           #    0.50% of all LL-cache accesses
+     2.000421010              7,000      LLC-load-misses                                               (50.00%)
+`, "LLC-load-misses"},
+		{"per-core", `#           time core                 cpus             counts unit events
+     1.000183237 S0-D0-C0           2              6,000      LLC-load-misses
+     1.000183237 S0-D0-C1           2                 21      LLC-load-misses
+     1.000183237 S0-D0-C2           2      <not counted>      LLC-load-misses
+     2.000421010 S0-D0-C0           2              7,000      LLC-load-misses
+`, "LLC-load-misses"},
+		{"no aggregation", `     1.000183237 CPU0               6,021      LLC-load-misses
+     1.000183237 CPU1      <not counted>      LLC-load-misses
+     2.000421010 CPU0               7,000      LLC-load-misses
+`, "LLC-load-misses"},
+		{"csv", `1.000183237,6021,,LLC-load-misses,1000123,100.00,,
+2.000421010,7000,,LLC-load-misses,1000123,100.00,,
+2.000421010,<not counted>,,cycles,0,100.00,,
+`, "LLC-load-misses"},
+		{"csv per-core with modifier", `1.000183237,S0-D0-C0,2,6021,,LLC-load-misses:u,1000123,100.00,,
+2.000421010,S0-D0-C0,2,7000,,LLC-load-misses:u,1000123,100.00,,
+2.000421010,S0-D0-C1,2,<not counted>,,LLC-load-misses:u,0,100.00,,
+`, "LLC-load-misses:u"},
+	}
+
+	for _, e := range expected {
+		series := ParsePerfIntervals(e.output, "LLC-load-misses")
+		if want := map[string][]PerfSampleT{e.event: samples}; !reflect.DeepEqual(series, want) {
+			t.Errorf("%v: ParsePerfIntervals returned %v, expected %v", e.name, series, want)
+		}
+	}
+
+	// hybrid CPUs count every event per PMU
+	output := `     1.000183237              6,000      cpu_core/LLC-load-misses/
+     1.000183237                 21      cpu_atom/LLC-load-misses/
+`
+	hybrid := map[string][]PerfSampleT{"cpu_core/LLC-load-misses/": {{1.000183237, 6000}}, "cpu_atom/LLC-load-misses/": {{1.000183237, 21}}}
+	if series := ParsePerfIntervals(output, "LLC-load-misses"); !reflect.DeepEqual(series, hybrid) {
+		t.Errorf("ParsePerfIntervals of hybrid CPUs returned %v, expected %v", series, hybrid)
+	}
+}
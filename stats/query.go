@@ -0,0 +1,106 @@
+package stats
+
+import (
+	"math"
+
+	"github.com/jbreitbart/coBench/commands"
+	"github.com/montanaflynn/stats"
+)
+
+// NoCoSched matches only the individual runs in FilterT.CoSched
+const NoCoSched = "none"
+
+// FilterT selects the configurations and runs of a query. Empty values match everything, applications match by the
+// command or by its name as shown in the plots.
+type FilterT struct {
+	App string
+	// co-scheduled application or NoCoSched
+	CoSched string
+	// number of CAT bits, NoCATMask for runs without CAT
+	CATBits *int
+	// only runs executed with this CAT mask
+	CATMask *uint64
+}
+
+// QueryResultT is the aggregated metric of the selected runs of a configuration
+type QueryResultT struct {
+	Config ConfigT
+	// values of the runs without warm-up runs and outliers
+	Values []float64
+	Mean   float64
+	Stddev float64
+	Median float64
+	P25    float64
+	P75    float64
+	Min    float64
+	Max    float64
+	// confidence interval of the mean, NaN for less than 2 runs
+	CI [2]float64
+}
+
+func matchesApp(filter string, app string) bool {
+	return filter == "" || filter == app || filter == commands.Pretty(app)
+}
+
+func (f *FilterT) matches(c ConfigT) bool {
+	if !matchesApp(f.App, c.App) {
+		return false
+	}
+	switch f.CoSched {
+	case "":
+	case NoCoSched:
+		if c.CoSched != "" {
+			return false
+		}
+	default:
+		if c.CoSched == "" || !matchesApp(f.CoSched, c.CoSched) {
+			return false
+		}
+	}
+	return f.CATBits == nil || *f.CATBits == c.CATBits
+}
+
+// Query aggregates the metric of every configuration of the dataset selected by the filter. Configurations without a
+// selected run are skipped.
+func Query(s *StatsT, f FilterT, metric string, level float64) []QueryResultT {
+	var ret []QueryResultT
+	for _, c := range s.Configs() {
+		if !f.matches(c) {
+			continue
+		}
+
+		q := QueryResultT{Config: c}
+		for mask, runs := range *s.Runtime(c).RawRuntimesByMask {
+			if f.CATMask != nil && *f.CATMask != mask {
+				continue
+			}
+			for _, r := range runs {
+				if r.Excluded() {
+					continue
+				}
+				if metric == RuntimeMetric || metric == "" {
+					q.Values = append(q.Values, r.Runtime.Seconds())
+				} else if value, exists := r.Metrics[metric]; exists {
+					q.Values = append(q.Values, value)
+				}
+			}
+		}
+		if len(q.Values) == 0 {
+			continue
+		}
+
+		q.Mean, _ = stats.Mean(q.Values)
+		q.Stddev, _ = stats.StandardDeviation(q.Values)
+		q.Median, _ = stats.Median(q.Values)
+		q.P25, _ = stats.Percentile(q.Values, 25)
+		q.P75, _ = stats.Percentile(q.Values, 75)
+		q.Min, _ = stats.Min(q.Values)
+		q.Max, _ = stats.Max(q.Values)
+		q.CI = [2]float64{math.NaN(), math.NaN()}
+		if ci, _, ok := ConfidenceInterval(q.Values, level); ok {
+			q.CI = ci
+		}
+		ret = append(ret, q)
+	}
+	return ret
+}
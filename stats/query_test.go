@@ -0,0 +1,52 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	catRuntime := newRuntimeT(0x3, secondsToRuns(2, 4))
+	catRuntime.update(0xc, secondsToRuns(6), &runtimeStats.Commandline)
+	cat := map[int]RuntimeT{2: catRuntime}
+	coSched := map[string]RuntimeT{"/bin/b -x": newRuntimeT(NoCATMask, secondsToRuns(3))}
+	d := StatsT{Runtimes: map[string]*RuntimePerAppT{
+		"/bin/a -x": {ReferenceRuntimes: newRuntimeT(NoCATMask, secondsToRuns(1, 2, 3)), CATRuntimes: &cat, CoSchedRuntimes: &coSched},
+		"/bin/b -x": {ReferenceRuntimes: newRuntimeT(NoCATMask, secondsToRuns(5))},
+	}}
+
+	two := 2
+	none := NoCATMask
+	mask := uint64(0x3)
+	expected := []struct {
+		filter  FilterT
+		configs []ConfigT
+		mean    float64
+	}{
+		{FilterT{App: "a", CoSched: NoCoSched, CATBits: &none}, []ConfigT{{App: "/bin/a -x"}}, 2},
+		{FilterT{App: "/bin/a -x", CATBits: &two}, []ConfigT{{App: "/bin/a -x", CATBits: 2}}, 4},
+		{FilterT{App: "a", CATMask: &mask}, []ConfigT{{App: "/bin/a -x", CATBits: 2}}, 3},
+		{FilterT{CoSched: "b"}, []ConfigT{{App: "/bin/a -x", CoSched: "/bin/b -x"}}, 3},
+		{FilterT{CoSched: NoCoSched, CATBits: &none}, []ConfigT{{App: "/bin/a -x"}, {App: "/bin/b -x"}}, 2},
+	}
+
+	for _, e := range expected {
+		result := Query(&d, e.filter, RuntimeMetric, DefaultCILevel)
+		if len(result) != len(e.configs) {
+			t.Errorf("Query(%+v) returned %+v, expected %v", e.filter, result, e.configs)
+			continue
+		}
+		for i, q := range result {
+			if q.Config != e.configs[i] {
+				t.Errorf("Query(%+v) returned %+v, expected %v", e.filter, q.Config, e.configs[i])
+			}
+		}
+		if math.Abs(result[0].Mean-e.mean) > 1e-9 {
+			t.Errorf("Query(%+v) has mean %v, expected %v", e.filter, result[0].Mean, e.mean)
+		}
+	}
+
+	if q := Query(&d, FilterT{App: "b"}, RuntimeMetric, DefaultCILevel); len(q) != 1 || !math.IsNaN(q[0].CI[0]) {
+		t.Errorf("A single run must not have a confidence interval: %+v", q)
+	}
+}
@@ -0,0 +1,125 @@
+package stats
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/montanaflynn/stats"
+)
+
+// number of resamples used for bootstrap confidence intervals
+const bootstrapResamples = 1000
+
+// fixed seed, so the bootstrap confidence intervals are reproducible
+const bootstrapSeed = 42
+
+// computes the order statistics and the bootstrap confidence interval of the runtimes
+func (run *RuntimeT) updateRobust(runtimeSeconds []float64, level float64) {
+	run.Median, run.Min, run.Max = 0, 0, 0
+	run.P5, run.P25, run.P75, run.P95 = 0, 0, 0, 0
+	run.CV = 0
+	run.BootstrapCI = [2]float64{}
+
+	if len(runtimeSeconds) == 0 {
+		return
+	}
+
+	run.Median, _ = stats.Median(runtimeSeconds)
+	run.Min, _ = stats.Min(runtimeSeconds)
+	run.Max, _ = stats.Max(runtimeSeconds)
+	run.P5, _ = stats.Percentile(runtimeSeconds, 5)
+	run.P25, _ = stats.Percentile(runtimeSeconds, 25)
+	run.P75, _ = stats.Percentile(runtimeSeconds, 75)
+	run.P95, _ = stats.Percentile(runtimeSeconds, 95)
+	if run.Mean != 0 {
+		run.CV = run.Stddev / run.Mean
+	}
+	if ci, ok := BootstrapMeanCI(runtimeSeconds, level); ok {
+		run.BootstrapCI = ci
+	}
+}
+
+// BootstrapMeanCI returns the percentile bootstrap confidence interval of the mean of values
+func BootstrapMeanCI(values []float64, level float64) ([2]float64, bool) {
+	if len(values) < 2 {
+		return [2]float64{}, false
+	}
+
+	rng := rand.New(rand.NewSource(bootstrapSeed))
+	means := make([]float64, bootstrapResamples)
+	for i := range means {
+		means[i] = resampledMean(rng, values)
+	}
+
+	return percentileInterval(means, level), true
+}
+
+// BootstrapSlowdownCI returns the percentile bootstrap confidence interval of the slowdown of run compared to ref
+func BootstrapSlowdownCI(run *RuntimeT, ref *RuntimeT, metric string, level float64) ([2]float64, bool) {
+	return runtimeStats.BootstrapSlowdownCI(run, ref, metric, level)
+}
+
+// BootstrapSlowdownCI returns the percentile bootstrap confidence interval of the slowdown of run compared to ref with the
+// metric rules of the stats
+func (s *StatsT) BootstrapSlowdownCI(run *RuntimeT, ref *RuntimeT, metric string, level float64) ([2]float64, bool) {
+	if run == nil || ref == nil {
+		return [2]float64{}, false
+	}
+	values := run.MetricValues(metric)
+	refValues := ref.MetricValues(metric)
+	if len(values) < 2 || len(refValues) < 2 {
+		return [2]float64{}, false
+	}
+
+	higherIsBetter := false
+	if rule := s.MetricRule(metric); rule != nil {
+		higherIsBetter = rule.HigherIsBetter
+	}
+
+	rng := rand.New(rand.NewSource(bootstrapSeed))
+	ratios := make([]float64, bootstrapResamples)
+	for i := range ratios {
+		mean := resampledMean(rng, values)
+		refMean := resampledMean(rng, refValues)
+		ratios[i] = mean / refMean
+		if higherIsBetter {
+			ratios[i] = refMean / mean
+		}
+	}
+
+	ci := percentileInterval(ratios, level)
+	if math.IsNaN(ci[0]) || math.IsInf(ci[0], 0) || math.IsNaN(ci[1]) || math.IsInf(ci[1], 0) {
+		return [2]float64{}, false
+	}
+	return ci, true
+}
+
+// MetricQuartiles returns the median and the first and third quartile of metric
+func (run *RuntimeT) MetricQuartiles(metric string) (median float64, p25 float64, p75 float64) {
+	if metric == RuntimeMetric || metric == "" {
+		return run.Median, run.P25, run.P75
+	}
+
+	values := run.MetricValues(metric)
+	if len(values) == 0 {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+	median, _ = stats.Median(values)
+	p25, _ = stats.Percentile(values, 25)
+	p75, _ = stats.Percentile(values, 75)
+	return
+}
+
+func resampledMean(rng *rand.Rand, values []float64) float64 {
+	sum := 0.0
+	for range values {
+		sum += values[rng.Intn(len(values))]
+	}
+	return sum / float64(len(values))
+}
+
+func percentileInterval(values []float64, level float64) [2]float64 {
+	lo, _ := stats.Percentile(values, 100*(1-level)/2)
+	hi, _ := stats.Percentile(values, 100*(1+level)/2)
+	return [2]float64{lo, hi}
+}
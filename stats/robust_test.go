@@ -0,0 +1,35 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRobustStatistics(t *testing.T) {
+	data := make([]DataPerRun, 11)
+	for i := range data {
+		data[i].Runtime = time.Duration(i+1) * time.Second
+	}
+	r := newRuntimeT(NoCATMask, data)
+
+	if r.Median != 6 || r.Min != 1 || r.Max != 11 || r.P25 != 3.5 || r.P75 != 8.5 || r.P5 != 1.5 || r.P95 != 10.5 {
+		t.Errorf("Unexpected order statistics %v %v %v %v %v %v %v", r.Median, r.Min, r.Max, r.P5, r.P25, r.P75, r.P95)
+	}
+	if math.Abs(r.CV-r.Stddev/6) > 1e-12 {
+		t.Errorf("Unexpected coefficient of variation %v", r.CV)
+	}
+	if r.BootstrapCI[0] >= r.Mean || r.BootstrapCI[1] <= r.Mean {
+		t.Errorf("Bootstrap confidence interval %v does not contain the mean %v", r.BootstrapCI, r.Mean)
+	}
+
+	slower := make([]DataPerRun, len(data))
+	for i := range slower {
+		slower[i].Runtime = 2 * data[i].Runtime
+	}
+	s := newRuntimeT(NoCATMask, slower)
+	ci, ok := BootstrapSlowdownCI(&s, &r, RuntimeMetric, 0.95)
+	if !ok || ci[0] >= 2 || ci[1] <= 2 {
+		t.Errorf("Bootstrap confidence interval %v of the slowdown does not contain 2", ci)
+	}
+}
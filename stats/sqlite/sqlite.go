@@ -0,0 +1,611 @@
+// Package sqlite stores coBench datasets in a SQLite database. Importing the package registers it as storage for
+// .db and .sqlite files.
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jbreitbart/coBench/commands"
+	"github.com/jbreitbart/coBench/stats"
+	// registers the sqlite3 driver
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func init() {
+	stats.RegisterStorage(".db", StorageT{})
+	stats.RegisterStorage(".sqlite", StorageT{})
+}
+
+// Schema of the database. Statistics of a configuration are stored as computed when the campaign was stored, the
+// runs of a configuration are grouped by the CAT mask they were executed with. Metrics contains the metrics reported
+// by the application (source app), the perf counter summaries (source perf) and the cgroup statistics (source cgroup)
+// named after their file, e.g. memory.peak, cpu.stat.usage_usec or io.pressure.some.total.
+const Schema = `
+CREATE TABLE IF NOT EXISTS hosts (
+	id INTEGER PRIMARY KEY,
+	hostname TEXT NOT NULL,
+	cpu_model TEXT NOT NULL,
+	kernel TEXT NOT NULL,
+	l3_size INTEGER NOT NULL,
+	l3_ways INTEGER NOT NULL,
+	l3_caches INTEGER NOT NULL,
+	cat_bits INTEGER NOT NULL,
+	UNIQUE (hostname, cpu_model, kernel, l3_size, l3_ways, l3_caches, cat_bits)
+);
+CREATE TABLE IF NOT EXISTS campaigns (
+	id INTEGER PRIMARY KEY,
+	name TEXT NOT NULL,
+	created TEXT NOT NULL,
+	host_id INTEGER NOT NULL REFERENCES hosts(id),
+	commandline TEXT NOT NULL,
+	metric_rules TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS applications (
+	id INTEGER PRIMARY KEY,
+	command TEXT NOT NULL UNIQUE,
+	name TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS configurations (
+	id INTEGER PRIMARY KEY,
+	campaign_id INTEGER NOT NULL REFERENCES campaigns(id),
+	app_id INTEGER NOT NULL REFERENCES applications(id),
+	corunner_id INTEGER REFERENCES applications(id),
+	cat_bits INTEGER NOT NULL,
+	cgroup TEXT NOT NULL DEFAULT '',
+	runs INTEGER NOT NULL,
+	outliers INTEGER NOT NULL,
+	mean REAL,
+	stddev REAL,
+	variance REAL,
+	runtime_sum REAL,
+	ci_level REAL,
+	ci_low REAL,
+	ci_high REAL,
+	ci_rel_half_width REAL,
+	median REAL,
+	min REAL,
+	max REAL,
+	p5 REAL,
+	p25 REAL,
+	p75 REAL,
+	p95 REAL,
+	cv REAL,
+	bootstrap_ci_low REAL,
+	bootstrap_ci_high REAL
+);
+CREATE TABLE IF NOT EXISTS runs (
+	id INTEGER PRIMARY KEY,
+	configuration_id INTEGER NOT NULL REFERENCES configurations(id),
+	cat_mask INTEGER NOT NULL,
+	run_index INTEGER NOT NULL,
+	start TEXT NOT NULL,
+	runtime_ns INTEGER NOT NULL,
+	warmup INTEGER NOT NULL,
+	outlier INTEGER NOT NULL,
+	output TEXT NOT NULL,
+	utime_ns INTEGER,
+	stime_ns INTEGER,
+	maxrss_kb INTEGER,
+	minflt INTEGER,
+	majflt INTEGER,
+	nvcsw INTEGER,
+	nivcsw INTEGER
+);
+CREATE TABLE IF NOT EXISTS metrics (
+	run_id INTEGER NOT NULL REFERENCES runs(id),
+	source TEXT NOT NULL,
+	name TEXT NOT NULL,
+	value REAL,
+	PRIMARY KEY (run_id, source, name)
+);
+CREATE TABLE IF NOT EXISTS perf_samples (
+	run_id INTEGER NOT NULL REFERENCES runs(id),
+	counter TEXT NOT NULL,
+	time REAL NOT NULL,
+	value REAL
+);
+CREATE INDEX IF NOT EXISTS configurations_campaign ON configurations(campaign_id);
+CREATE INDEX IF NOT EXISTS runs_configuration ON runs(configuration_id);
+CREATE INDEX IF NOT EXISTS perf_samples_run ON perf_samples(run_id);
+`
+
+const (
+	appMetric    = "app"
+	perfMetric   = "perf"
+	cgroupMetric = "cgroup"
+)
+
+// StorageT implements stats.StorageT
+type StorageT struct{}
+
+// Open opens or creates the database and its tables
+func Open(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?_foreign_keys=1")
+	if err != nil {
+		return nil, err
+	}
+	if _, err = db.Exec(Schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("Cannot create the tables in %v: %v", path, err)
+	}
+	return db, nil
+}
+
+// Store adds the dataset as a new campaign
+func (StorageT) Store(path string, name string, s *stats.StatsT) (int64, error) {
+	db, err := Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	id, err := store(tx, name, s)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+func store(tx *sql.Tx, name string, s *stats.StatsT) (int64, error) {
+	hw := s.Hardware
+	host := []interface{}{hw.Hostname, hw.CPUModel, hw.Kernel, int64(hw.L3Size), hw.L3Ways, hw.L3Caches, hw.CATBits}
+	_, err := tx.Exec("INSERT OR IGNORE INTO hosts (hostname, cpu_model, kernel, l3_size, l3_ways, l3_caches, cat_bits) VALUES (?, ?, ?, ?, ?, ?, ?)", host...)
+	if err != nil {
+		return 0, err
+	}
+	var hostID int64
+	err = tx.QueryRow(`SELECT id FROM hosts WHERE hostname = ? AND cpu_model = ? AND kernel = ? AND l3_size = ? AND l3_ways = ?
+		AND l3_caches = ? AND cat_bits = ?`, host...).Scan(&hostID)
+	if err != nil {
+		return 0, err
+	}
+
+	commandline, err := json.Marshal(s.Commandline)
+	if err != nil {
+		return 0, err
+	}
+	rules, err := json.Marshal(s.MetricRules)
+	if err != nil {
+		return 0, err
+	}
+	created := time.Now()
+	if name == "" {
+		name = created.Format(time.RFC3339)
+	}
+	res, err := tx.Exec("INSERT INTO campaigns (name, created, host_id, commandline, metric_rules) VALUES (?, ?, ?, ?, ?)",
+		name, created.Format(time.RFC3339Nano), hostID, string(commandline), string(rules))
+	if err != nil {
+		return 0, err
+	}
+	campaignID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	appIDs := make(map[string]int64)
+	appID := func(command string) (int64, error) {
+		if id, exists := appIDs[command]; exists {
+			return id, nil
+		}
+		if _, err := tx.Exec("INSERT OR IGNORE INTO applications (command, name) VALUES (?, ?)", command, commands.Pretty(command)); err != nil {
+			return 0, err
+		}
+		var id int64
+		err := tx.QueryRow("SELECT id FROM applications WHERE command = ?", command).Scan(&id)
+		appIDs[command] = id
+		return id, err
+	}
+
+	for _, c := range s.Configs() {
+		app, err := appID(c.App)
+		if err != nil {
+			return 0, err
+		}
+		var corunner sql.NullInt64
+		if c.CoSched != "" {
+			corunner.Int64, err = appID(c.CoSched)
+			if err != nil {
+				return 0, err
+			}
+			corunner.Valid = true
+		}
+
+		r := s.Runtime(c)
+		res, err := tx.Exec(`INSERT INTO configurations (campaign_id, app_id, corunner_id, cat_bits, cgroup, runs, outliers, mean,
+			stddev, variance, runtime_sum, ci_level, ci_low, ci_high, ci_rel_half_width, median, min, max, p5, p25, p75, p95, cv,
+			bootstrap_ci_low, bootstrap_ci_high) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			campaignID, app, corunner, c.CATBits, c.Cgroup, r.Runs, r.Outliers, r.Mean, r.Stddev, r.Vari, r.RuntimeSum, r.CILevel, r.CI[0],
+			r.CI[1], r.CIRelHalfWidth, r.Median, r.Min, r.Max, r.P5, r.P25, r.P75, r.P95, r.CV, r.BootstrapCI[0], r.BootstrapCI[1])
+		if err != nil {
+			return 0, err
+		}
+		configID, err := res.LastInsertId()
+		if err != nil {
+			return 0, err
+		}
+
+		for mask, data := range *r.RawRuntimesByMask {
+			for i := range data {
+				if err = storeRun(tx, configID, mask, i, &data[i]); err != nil {
+					return 0, err
+				}
+			}
+		}
+	}
+
+	return campaignID, nil
+}
+
+func storeRun(tx *sql.Tx, configID int64, mask uint64, index int, data *stats.DataPerRun) error {
+	var rusage [7]sql.NullInt64
+	if u := data.Rusage; u != nil {
+		for i, v := range []int64{int64(u.UserTime), int64(u.SystemTime), u.MaxRSS, u.MinorFaults, u.MajorFaults, u.VoluntaryContextSwitches, u.InvoluntaryContextSwitches} {
+			rusage[i] = sql.NullInt64{Int64: v, Valid: true}
+		}
+	}
+
+	res, err := tx.Exec(`INSERT INTO runs (configuration_id, cat_mask, run_index, start, runtime_ns, warmup, outlier, output,
+		utime_ns, stime_ns, maxrss_kb, minflt, majflt, nvcsw, nivcsw) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		configID, int64(mask), index, data.Start.Format(time.RFC3339Nano), int64(data.Runtime), data.Warmup, data.Outlier, data.Output,
+		rusage[0], rusage[1], rusage[2], rusage[3], rusage[4], rusage[5], rusage[6])
+	if err != nil {
+		return err
+	}
+	runID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	for name, value := range data.Metrics {
+		if _, err = tx.Exec("INSERT INTO metrics (run_id, source, name, value) VALUES (?, ?, ?, ?)", runID, appMetric, name, value); err != nil {
+			return err
+		}
+	}
+	for name, value := range stats.ParsePerfCounters(data.Output) {
+		if _, err = tx.Exec("INSERT INTO metrics (run_id, source, name, value) VALUES (?, ?, ?, ?)", runID, perfMetric, name, value); err != nil {
+			return err
+		}
+	}
+	if data.Cgroup != nil {
+		for name, value := range cgroupMetrics(data.Cgroup) {
+			if _, err = tx.Exec("INSERT INTO metrics (run_id, source, name, value) VALUES (?, ?, ?, ?)", runID, cgroupMetric, name, value); err != nil {
+				return err
+			}
+		}
+	}
+	for counter, samples := range data.PerfSeries {
+		for _, sample := range samples {
+			if _, err = tx.Exec("INSERT INTO perf_samples (run_id, counter, time, value) VALUES (?, ?, ?, ?)", runID, counter, sample.Time, sample.Value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Read returns the campaign, the latest one if id is stats.LatestCampaign
+func (StorageT) Read(path string, id int64) (*stats.StatsT, error) {
+	db, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	if id == stats.LatestCampaign {
+		var latest sql.NullInt64
+		if err = db.QueryRow("SELECT max(id) FROM campaigns").Scan(&latest); err != nil {
+			return nil, err
+		}
+		if !latest.Valid {
+			return nil, fmt.Errorf("No campaigns stored in %v", path)
+		}
+		id = latest.Int64
+	}
+
+	var ret stats.StatsT
+	var commandline, rules string
+	var l3Size int64
+	err = db.QueryRow(`SELECT c.commandline, c.metric_rules, h.hostname, h.cpu_model, h.kernel, h.l3_size, h.l3_ways, h.l3_caches,
+		h.cat_bits FROM campaigns c JOIN hosts h ON h.id = c.host_id WHERE c.id = ?`, id).Scan(&commandline, &rules, &ret.Hardware.Hostname,
+		&ret.Hardware.CPUModel, &ret.Hardware.Kernel, &l3Size, &ret.Hardware.L3Ways, &ret.Hardware.L3Caches, &ret.Hardware.CATBits)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("Campaign %v is not stored in %v", id, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	ret.Hardware.L3Size = uint64(l3Size)
+	if err = json.Unmarshal([]byte(commandline), &ret.Commandline); err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal([]byte(rules), &ret.MetricRules); err != nil {
+		return nil, err
+	}
+
+	runtimes, err := readConfigs(db, id)
+	if err != nil {
+		return nil, err
+	}
+	for configID, r := range runtimes {
+		if err = readRuns(db, configID, r.runtime); err != nil {
+			return nil, err
+		}
+		ret.SetRuntime(r.config, *r.runtime)
+	}
+
+	return &ret, nil
+}
+
+type configRuntimeT struct {
+	config  stats.ConfigT
+	runtime *stats.RuntimeT
+}
+
+// statistics are NULL if they were NaN
+func nullToNaN(values []sql.NullFloat64, targets ...*float64) {
+	for i, v := range values {
+		*targets[i] = math.NaN()
+		if v.Valid {
+			*targets[i] = v.Float64
+		}
+	}
+}
+
+func readConfigs(db *sql.DB, campaignID int64) (map[int64]configRuntimeT, error) {
+	rows, err := db.Query(`SELECT c.id, a.command, coalesce(o.command, ''), c.cat_bits, c.cgroup, c.runs, c.outliers, c.mean, c.stddev,
+		c.variance, c.runtime_sum, c.ci_level, c.ci_low, c.ci_high, c.ci_rel_half_width, c.median, c.min, c.max, c.p5, c.p25,
+		c.p75, c.p95, c.cv, c.bootstrap_ci_low, c.bootstrap_ci_high FROM configurations c JOIN applications a ON a.id = c.app_id
+		LEFT JOIN applications o ON o.id = c.corunner_id WHERE c.campaign_id = ?`, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret := make(map[int64]configRuntimeT)
+	for rows.Next() {
+		var id int64
+		var c stats.ConfigT
+		var r stats.RuntimeT
+		var values [18]sql.NullFloat64
+		dest := []interface{}{&id, &c.App, &c.CoSched, &c.CATBits, &c.Cgroup, &r.Runs, &r.Outliers}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		if err = rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		nullToNaN(values[:], &r.Mean, &r.Stddev, &r.Vari, &r.RuntimeSum, &r.CILevel, &r.CI[0], &r.CI[1], &r.CIRelHalfWidth, &r.Median,
+			&r.Min, &r.Max, &r.P5, &r.P25, &r.P75, &r.P95, &r.CV, &r.BootstrapCI[0], &r.BootstrapCI[1])
+		ret[id] = configRuntimeT{config: c, runtime: &r}
+	}
+	return ret, rows.Err()
+}
+
+func readRuns(db *sql.DB, configID int64, r *stats.RuntimeT) error {
+	rows, err := db.Query(`SELECT id, cat_mask, start, runtime_ns, warmup, outlier, output, utime_ns, stime_ns, maxrss_kb, minflt,
+		majflt, nvcsw, nivcsw FROM runs WHERE configuration_id = ? ORDER BY cat_mask, run_index`, configID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	byMask := make(map[uint64][]stats.DataPerRun)
+	type runRefT struct {
+		mask  uint64
+		index int
+	}
+	runIDs := make(map[int64]runRefT)
+	for rows.Next() {
+		var id, mask, runtime int64
+		var start string
+		var data stats.DataPerRun
+		var rusage [7]sql.NullInt64
+		if err = rows.Scan(&id, &mask, &start, &runtime, &data.Warmup, &data.Outlier, &data.Output, &rusage[0], &rusage[1], &rusage[2],
+			&rusage[3], &rusage[4], &rusage[5], &rusage[6]); err != nil {
+			return err
+		}
+		data.Runtime = time.Duration(runtime)
+		if data.Start, err = time.Parse(time.RFC3339Nano, start); err != nil {
+			return err
+		}
+		if rusage[0].Valid {
+			data.Rusage = &stats.RusageT{
+				UserTime:                   time.Duration(rusage[0].Int64),
+				SystemTime:                 time.Duration(rusage[1].Int64),
+				MaxRSS:                     rusage[2].Int64,
+				MinorFaults:                rusage[3].Int64,
+				MajorFaults:                rusage[4].Int64,
+				VoluntaryContextSwitches:   rusage[5].Int64,
+				InvoluntaryContextSwitches: rusage[6].Int64,
+			}
+		}
+		runIDs[id] = runRefT{mask: uint64(mask), index: len(byMask[uint64(mask)])}
+		byMask[uint64(mask)] = append(byMask[uint64(mask)], data)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for id, ref := range runIDs {
+		data := &byMask[ref.mask][ref.index]
+		if data.Metrics, err = readMetrics(db, id); err != nil {
+			return err
+		}
+		if data.PerfSeries, err = readPerfSamples(db, id); err != nil {
+			return err
+		}
+		if data.Cgroup, err = readCgroupStats(db, id); err != nil {
+			return err
+		}
+	}
+
+	r.RawRuntimesByMask = &byMask
+	return nil
+}
+
+// nil if the run has no metrics, as in the JSON files
+func readMetrics(db *sql.DB, runID int64) (map[string]float64, error) {
+	rows, err := db.Query("SELECT name, value FROM metrics WHERE run_id = ? AND source = ?", runID, appMetric)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ret map[string]float64
+	for rows.Next() {
+		var name string
+		var value sql.NullFloat64
+		if err = rows.Scan(&name, &value); err != nil {
+			return nil, err
+		}
+		if ret == nil {
+			ret = make(map[string]float64)
+		}
+		ret[name] = math.NaN()
+		if value.Valid {
+			ret[name] = value.Float64
+		}
+	}
+	return ret, rows.Err()
+}
+
+// the cgroup statistics as metrics named after their file
+func cgroupMetrics(c *stats.CgroupStatsT) map[string]float64 {
+	ret := map[string]float64{"memory.peak": float64(c.MemoryPeak)}
+	for key, v := range c.CPUStat {
+		ret["cpu.stat."+key] = float64(v)
+	}
+	for resource, lines := range c.Pressure {
+		for line, p := range lines {
+			prefix := resource + ".pressure." + line + "."
+			ret[prefix+"avg10"] = p.Avg10
+			ret[prefix+"avg60"] = p.Avg60
+			ret[prefix+"avg300"] = p.Avg300
+			ret[prefix+"total"] = float64(p.Total)
+		}
+	}
+	return ret
+}
+
+// inverse of cgroupMetrics, nil if the run was not executed in a cgroup
+func readCgroupStats(db *sql.DB, runID int64) (*stats.CgroupStatsT, error) {
+	rows, err := db.Query("SELECT name, value FROM metrics WHERE run_id = ? AND source = ?", runID, cgroupMetric)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ret *stats.CgroupStatsT
+	for rows.Next() {
+		var name string
+		var value float64
+		if err = rows.Scan(&name, &value); err != nil {
+			return nil, err
+		}
+		if ret == nil {
+			ret = &stats.CgroupStatsT{}
+		}
+
+		if name == "memory.peak" {
+			ret.MemoryPeak = uint64(value)
+		} else if strings.HasPrefix(name, "cpu.stat.") {
+			if ret.CPUStat == nil {
+				ret.CPUStat = make(map[string]uint64)
+			}
+			ret.CPUStat[strings.TrimPrefix(name, "cpu.stat.")] = uint64(value)
+		} else if parts := strings.SplitN(name, ".pressure.", 2); len(parts) == 2 {
+			field := strings.SplitN(parts[1], ".", 2)
+			if len(field) != 2 {
+				continue
+			}
+			if ret.Pressure == nil {
+				ret.Pressure = make(map[string]map[string]stats.PressureT)
+			}
+			if ret.Pressure[parts[0]] == nil {
+				ret.Pressure[parts[0]] = make(map[string]stats.PressureT)
+			}
+			p := ret.Pressure[parts[0]][field[0]]
+			switch field[1] {
+			case "avg10":
+				p.Avg10 = value
+			case "avg60":
+				p.Avg60 = value
+			case "avg300":
+				p.Avg300 = value
+			case "total":
+				p.Total = uint64(value)
+			}
+			ret.Pressure[parts[0]][field[0]] = p
+		}
+	}
+	return ret, rows.Err()
+}
+
+func readPerfSamples(db *sql.DB, runID int64) (map[string][]stats.PerfSampleT, error) {
+	rows, err := db.Query("SELECT counter, time, value FROM perf_samples WHERE run_id = ? ORDER BY rowid", runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ret map[string][]stats.PerfSampleT
+	for rows.Next() {
+		var counter string
+		var sample stats.PerfSampleT
+		var value sql.NullFloat64
+		if err = rows.Scan(&counter, &sample.Time, &value); err != nil {
+			return nil, err
+		}
+		sample.Value = math.NaN()
+		if value.Valid {
+			sample.Value = value.Float64
+		}
+		if ret == nil {
+			ret = make(map[string][]stats.PerfSampleT)
+		}
+		ret[counter] = append(ret[counter], sample)
+	}
+	return ret, rows.Err()
+}
+
+// Campaigns lists all campaigns stored in the database
+func (StorageT) Campaigns(path string) ([]stats.CampaignT, error) {
+	db, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT c.id, c.name, c.created,
+		(SELECT count(*) FROM configurations WHERE campaign_id = c.id),
+		(SELECT count(*) FROM runs r JOIN configurations f ON f.id = r.configuration_id WHERE f.campaign_id = c.id)
+		FROM campaigns c ORDER BY c.id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ret []stats.CampaignT
+	for rows.Next() {
+		var c stats.CampaignT
+		var created string
+		if err = rows.Scan(&c.ID, &c.Name, &created, &c.Configs, &c.Runs); err != nil {
+			return nil, err
+		}
+		if c.Created, err = time.Parse(time.RFC3339Nano, created); err != nil {
+			return nil, err
+		}
+		ret = append(ret, c)
+	}
+	return ret, rows.Err()
+}
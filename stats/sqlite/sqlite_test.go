@@ -0,0 +1,91 @@
+package sqlite
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jbreitbart/coBench/stats"
+)
+
+func TestStoreRead(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "results.db")
+
+	run := func(seconds float64) stats.DataPerRun {
+		return stats.DataPerRun{Runtime: time.Duration(seconds * float64(time.Second)), Start: time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)}
+	}
+	runs := []stats.DataPerRun{run(1), run(2), run(3)}
+	runs[0].Metrics = map[string]float64{"throughput": 5}
+	runs[0].Rusage = &stats.RusageT{UserTime: time.Second, MaxRSS: 1024}
+	runs[0].PerfSeries = map[string][]stats.PerfSampleT{"LLC-load-misses": {{Time: 1, Value: 10}, {Time: 2, Value: 20}}}
+	runs[0].Output = " Performance counter stats for 'a':\n\n   1000       LLC-load-misses\n\n"
+	runs[2].Warmup = true
+
+	d := stats.StatsT{
+		Commandline: stats.CommandlineT{Commands: []string{"./a", "./b"}, Runs: 3},
+		Hardware: stats.HardwareT{L3Size: 30 << 20, L3Ways: 20, L3Caches: 1, CATBits: 20, Hostname: "node1",
+			CPUModel: "Intel(R) Xeon(R) CPU E5-2630 v4 @ 2.20GHz", Kernel: "6.1.0-13-amd64"},
+		MetricRules: []stats.MetricRuleT{{Name: "throughput", Regex: "(\\d+)"}},
+	}
+	d.SetRuntime(stats.ConfigT{App: "./a"}, stats.RuntimeT{Mean: 1.5, Runs: 2, RawRuntimesByMask: &map[uint64][]stats.DataPerRun{0: runs}})
+	d.SetRuntime(stats.ConfigT{App: "./a", CoSched: "./b", CATBits: 2}, stats.RuntimeT{Mean: 4, Runs: 1,
+		RawRuntimesByMask: &map[uint64][]stats.DataPerRun{0x3: {run(4)}, 0xc: {run(4)}}})
+	limited := run(5)
+	limited.Cgroup = &stats.CgroupStatsT{MemoryPeak: 1 << 30, CPUStat: map[string]uint64{"usage_usec": 4000000, "nr_throttled": 3},
+		Pressure: map[string]map[string]stats.PressureT{"io": {"some": {Avg10: 1.5, Total: 1200}, "full": {Total: 800}}}}
+	d.SetRuntime(stats.ConfigT{App: "./a", CoSched: "./b", Cgroup: "io"}, stats.RuntimeT{Mean: 5, Runs: 1,
+		RawRuntimesByMask: &map[uint64][]stats.DataPerRun{0: {limited}}})
+
+	var s StorageT
+	for i := 1; i <= 2; i++ {
+		id, err := s.Store(path, "campaign", &d)
+		if err != nil || id != int64(i) {
+			t.Fatalf("Store returned %v, %v", id, err)
+		}
+	}
+
+	campaigns, err := s.Campaigns(path)
+	if err != nil || len(campaigns) != 2 || campaigns[1].Configs != 3 || campaigns[1].Runs != 6 {
+		t.Fatalf("Unexpected campaigns %+v, %v", campaigns, err)
+	}
+
+	for _, id := range []int64{stats.LatestCampaign, 1} {
+		read, err := s.Read(path, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, _ := json.Marshal(d)
+		got, _ := json.Marshal(read)
+		if string(expected) != string(got) {
+			t.Errorf("Campaign %v differs after reading\n%s\n%s", id, got, expected)
+		}
+	}
+
+	if _, err = s.Read(path, 3); err == nil {
+		t.Errorf("Reading a missing campaign succeeded")
+	}
+
+	// a machine with the same L3 cache is a different host
+	other := d
+	other.Hardware.Hostname = "node2"
+	if _, err = s.Store(path, "other", &other); err != nil {
+		t.Fatal(err)
+	}
+	db, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var hosts int
+	if err = db.QueryRow("SELECT count(*) FROM hosts").Scan(&hosts); err != nil || hosts != 2 {
+		t.Errorf("%v hosts stored, expected 2: %v", hosts, err)
+	}
+}
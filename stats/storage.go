@@ -0,0 +1,58 @@
+package stats
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CampaignT is a dataset stored in a storage holding multiple datasets
+type CampaignT struct {
+	ID      int64
+	Name    string
+	Created time.Time
+	Configs int
+	Runs    int
+}
+
+// StorageT stores datasets in a database instead of a JSON file. Every stored dataset becomes a new campaign, named
+// after its creation time if the name is empty.
+// Locations are paths with an optional campaign id, e.g. results.db#3. Without an id the latest campaign is read.
+type StorageT interface {
+	Store(path string, name string, s *StatsT) (id int64, err error)
+	Read(path string, id int64) (*StatsT, error)
+	Campaigns(path string) ([]CampaignT, error)
+}
+
+// storages by file extension
+var storages = make(map[string]StorageT)
+
+// RegisterStorage uses the storage for all files with the extension, e.g. ".db"
+func RegisterStorage(ext string, s StorageT) {
+	storages[ext] = s
+}
+
+// LatestCampaign selects the latest campaign when reading a storage
+const LatestCampaign = 0
+
+// SplitLocation splits a location into the path and the campaign id
+func SplitLocation(location string) (path string, id int64, err error) {
+	pos := strings.LastIndex(location, "#")
+	if pos == -1 {
+		return location, LatestCampaign, nil
+	}
+	if _, err = fmt.Sscanf(location[pos+1:], "%d", &id); err != nil || id <= 0 {
+		return "", 0, fmt.Errorf("Invalid campaign in %v", location)
+	}
+	return location[:pos], id, nil
+}
+
+// GetStorage returns the storage used for the location, nil for JSON files
+func GetStorage(location string) StorageT {
+	path, _, err := SplitLocation(location)
+	if err != nil {
+		path = location
+	}
+	return storages[filepath.Ext(path)]
+}
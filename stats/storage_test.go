@@ -0,0 +1,27 @@
+package stats
+
+import (
+	"testing"
+)
+
+func TestSplitLocation(t *testing.T) {
+	expected := []struct {
+		location string
+		path     string
+		id       int64
+		valid    bool
+	}{
+		{"results.db", "results.db", LatestCampaign, true},
+		{"dir/results.db#12", "dir/results.db", 12, true},
+		{"results.db#", "", 0, false},
+		{"results.db#0", "", 0, false},
+		{"results.db#x", "", 0, false},
+	}
+
+	for _, e := range expected {
+		path, id, err := SplitLocation(e.location)
+		if (err == nil) != e.valid || path != e.path || id != e.id {
+			t.Errorf("SplitLocation(%q) = %q, %v, %v", e.location, path, id, err)
+		}
+	}
+}
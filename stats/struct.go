@@ -9,23 +9,87 @@ const NoCATMask = 0
 
 // CommandlineT is used to store all command line parameters
 type CommandlineT struct {
-	Runs         int
-	VarianceDiff float64
-	CPUs         [2]string
-	Threads      string
-	HermitCore   bool
-	CAT          bool
-	CATChunk     uint64
-	CATDirs      []string
-	ResctrlPath  string
-	Commands     []string
+	Runs             int
+	VarianceDiff     float64
+	CPUs             [2]string
+	Threads          string
+	HermitCore       bool
+	CAT              bool
+	CATChunk         uint64
+	CATDirs          []string
+	ResctrlPath      string
+	Commands         []string
+	PerfStat         string
+	PerfInterval     int
+	CIWidth          float64
+	CILevel          float64
+	MaxRuns          int
+	WarmupRuns       int
+	OutlierMethod    string
+	OutlierThreshold float64
+	// specification of the launcher of the applications without an entry in Launchers, empty for the shell launcher
+	Launcher string `json:",omitempty"`
+	// launcher specification by command
+	Launchers map[string]string `json:",omitempty"`
+	// root of the cgroups of the applications, empty without cgroups
+	CgroupRoot string `json:",omitempty"`
+	// cgroup settings swept like the CAT settings
+	Cgroups []CgroupSettingT `json:",omitempty"`
 	// TODO update!
 }
 
+// PerfSampleT is a single value of a perf counter sampled with perf stat -I
+type PerfSampleT struct {
+	// time in seconds since the start of the run
+	Time  float64
+	Value float64
+}
+
+// HardwareT describes the cache of the machine the benchmarks were executed on. Zero values are unknown.
+type HardwareT struct {
+	// size of one L3 cache in bytes
+	L3Size uint64
+	L3Ways int
+	// number of L3 caches, usually the number of sockets
+	L3Caches int
+	// number of bits of the CAT capacity bitmask
+	CATBits int
+	// host name, CPU model and kernel release of the machine. Empty for result files created before they were recorded.
+	Hostname string `json:",omitempty"`
+	CPUModel string `json:",omitempty"`
+	Kernel   string `json:",omitempty"`
+}
+
+// RusageT is the resource usage of a run as reported by the kernel, including the waited for children
+type RusageT struct {
+	UserTime   time.Duration
+	SystemTime time.Duration
+	// maximum resident set size in KB
+	MaxRSS                     int64
+	MinorFaults                int64
+	MajorFaults                int64
+	VoluntaryContextSwitches   int64
+	InvoluntaryContextSwitches int64
+}
+
 // DataPerRun is the data we store for every run
 type DataPerRun struct {
 	Runtime time.Duration
 	Output  string
+	// wall clock time the run was started, used to align co-scheduled runs
+	Start time.Time
+	// perf counter time series by counter name (only if perf stat -I was used)
+	PerfSeries map[string][]PerfSampleT `json:",omitempty"`
+	// metrics reported by the application, extracted with the metric rules
+	Metrics map[string]float64 `json:",omitempty"`
+	// nil for result files created before it was recorded and for applications not started as children of the
+	// launched program, e.g. containers
+	Rusage *RusageT `json:",omitempty"`
+	// resource usage reported by the cgroup of the run, nil without cgroups
+	Cgroup *CgroupStatsT `json:",omitempty"`
+	// warm-up runs and outliers are stored, but not used for any statistics
+	Warmup  bool `json:",omitempty"`
+	Outlier bool `json:",omitempty"`
 }
 
 // RuntimeT contains a set of runtimes and statistic values
@@ -35,7 +99,24 @@ type RuntimeT struct {
 	Vari              float64
 	RuntimeSum        float64
 	Runs              int
+	Outliers          int
 	RawRuntimesByMask *map[uint64][]DataPerRun
+
+	// confidence interval of the mean runtime, zero if less than 2 runs
+	CILevel        float64
+	CI             [2]float64
+	CIRelHalfWidth float64
+
+	// robust statistics of the runtime
+	Median      float64
+	Min         float64
+	Max         float64
+	P5          float64
+	P25         float64
+	P75         float64
+	P95         float64
+	CV          float64
+	BootstrapCI [2]float64
 }
 
 // RuntimePerAppT store runtime values with different combinations for one application
@@ -51,6 +132,12 @@ type RuntimePerAppT struct {
 
 	// runtime coScheduling with CAT
 	CoSchedCATRuntimes *map[string]map[int]RuntimeT
+
+	// individual runtime by cgroup setting
+	CgroupRuntimes *map[string]RuntimeT `json:",omitempty"`
+
+	// runtime coScheduling by co-scheduled application and cgroup setting
+	CoSchedCgroupRuntimes *map[string]map[string]RuntimeT `json:",omitempty"`
 }
 
 // StatsT contains every information of a benchmark run
@@ -61,7 +148,11 @@ type StatsT struct {
 	// Command line options passed to coBench
 	Commandline CommandlineT
 
-	// TODO add hardware info
+	// Rules used to extract application reported metrics
+	MetricRules []MetricRuleT
+
+	// Machine the benchmarks were executed on
+	Hardware HardwareT
 
 	// TODO version info which struct version is used
 }